@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config represents the application configuration
+type Config struct {
+	ServerPort         string
+	Environment        string
+	APIBaseURL         string
+	APITimeout         time.Duration
+	LLMProvider        string
+	LLMTimeout         time.Duration
+	LLMMaxTokens       int
+	LLMTemperature     float64
+	OpenAIAPIKey       string
+	OpenAIBaseURL      string
+	OpenAIModel        string
+	CompatibleBaseURL  string
+	CompatibleModel    string
+	CompatibleAPIKey   string
+	CachePath          string
+	AdminToken         string
+	APIKeys            []string
+	AIPrices           map[string]Price
+	AIDailyBudget      float64
+	AIMonthlyBudget    float64
+	AIKeyDailyBudget   float64
+	AIKeyMonthlyBudget float64
+}
+
+// Price represents the price of a model in US dollars per million tokens
+type Price struct {
+	Prompt     float64
+	Completion float64
+}
+
+// defaultPrices holds the prices of the default OpenAI models
+var defaultPrices = map[string]Price{
+	"gpt-4o-mini": {Prompt: 0.15, Completion: 0.60},
+	"gpt-4o":      {Prompt: 2.50, Completion: 10.00},
+}
+
+// New creates a new instance of Config
+func New() *Config {
+	return &Config{
+		ServerPort:         getEnv("PORT", "8081"),
+		Environment:        getEnv("ENVIRONMENT", "development"),
+		APIBaseURL:         getEnv("API_BASE_URL", "http://localhost:8080/api/v1"),
+		APITimeout:         getEnvDuration("API_TIMEOUT", 10*time.Second),
+		LLMProvider:        getEnv("LLM_PROVIDER", "openai"),
+		LLMTimeout:         getEnvDuration("LLM_TIMEOUT", 30*time.Second),
+		LLMMaxTokens:       getEnvInt("LLM_MAX_TOKENS", 400),
+		LLMTemperature:     getEnvFloat("LLM_TEMPERATURE", 0.7),
+		OpenAIAPIKey:       getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL:      getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		OpenAIModel:        getEnv("OPENAI_MODEL", "gpt-4o-mini"),
+		CompatibleBaseURL:  getEnv("LLM_BASE_URL", "http://localhost:11434/v1"),
+		CompatibleModel:    getEnv("LLM_MODEL", "llama3.1"),
+		CompatibleAPIKey:   getEnv("LLM_API_KEY", ""),
+		CachePath:          getEnv("CACHE_PATH", ""),
+		AdminToken:         getEnv("ADMIN_TOKEN", ""),
+		APIKeys:            getEnvList("API_KEYS"),
+		AIPrices:           getEnvPrices("AI_PRICES", defaultPrices),
+		AIDailyBudget:      getEnvFloat("AI_DAILY_BUDGET", 0),
+		AIMonthlyBudget:    getEnvFloat("AI_MONTHLY_BUDGET", 0),
+		AIKeyDailyBudget:   getEnvFloat("AI_KEY_DAILY_BUDGET", 0),
+		AIKeyMonthlyBudget: getEnvFloat("AI_KEY_MONTHLY_BUDGET", 0),
+	}
+}
+
+// getEnv returns the value of the environment variable or the default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvInt returns the environment variable parsed as an integer or the default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvFloat returns the environment variable parsed as a float or the default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration returns the environment variable parsed as a duration (e.g. "30s", "24h") or the default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList returns the environment variable parsed as a comma-separated
+// list, skipping empty items
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvPrices returns the environment variable parsed as a comma-separated
+// list of model=prompt/completion prices (e.g. "gpt-4o-mini=0.15/0.60"), or
+// the default value if it is unset or invalid
+func getEnvPrices(key string, defaultValue map[string]Price) map[string]Price {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	prices, err := parsePrices(value)
+	if err != nil {
+		log.Printf("Invalid %s, using the default prices: %v", key, err)
+		return defaultValue
+	}
+	return prices
+}
+
+// parsePrices parses a comma-separated list of model=prompt/completion prices
+func parsePrices(value string) (map[string]Price, error) {
+	prices := make(map[string]Price)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		model, price, ok := strings.Cut(item, "=")
+		if !ok || model == "" {
+			return nil, fmt.Errorf("%q is not model=prompt/completion", item)
+		}
+		prompt, completion, ok := strings.Cut(price, "/")
+		if !ok {
+			return nil, fmt.Errorf("%q is not model=prompt/completion", item)
+		}
+
+		var err error
+		var p Price
+		if p.Prompt, err = strconv.ParseFloat(prompt, 64); err != nil {
+			return nil, fmt.Errorf("prompt price of %s: %w", model, err)
+		}
+		if p.Completion, err = strconv.ParseFloat(completion, 64); err != nil {
+			return nil, fmt.Errorf("completion price of %s: %w", model, err)
+		}
+		prices[model] = p
+	}
+	return prices, nil
+}
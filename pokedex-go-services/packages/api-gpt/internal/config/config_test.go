@@ -0,0 +1,151 @@
+package config
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	// Clear environment variables for testing
+	os.Unsetenv("PORT")
+	os.Unsetenv("ENVIRONMENT")
+	os.Unsetenv("API_BASE_URL")
+	os.Unsetenv("API_TIMEOUT")
+	os.Unsetenv("LLM_PROVIDER")
+	os.Unsetenv("LLM_TIMEOUT")
+	os.Unsetenv("LLM_MAX_TOKENS")
+	os.Unsetenv("LLM_TEMPERATURE")
+	os.Unsetenv("OPENAI_API_KEY")
+	os.Unsetenv("OPENAI_BASE_URL")
+	os.Unsetenv("OPENAI_MODEL")
+	os.Unsetenv("LLM_BASE_URL")
+	os.Unsetenv("LLM_MODEL")
+	os.Unsetenv("LLM_API_KEY")
+	os.Unsetenv("CACHE_PATH")
+	os.Unsetenv("ADMIN_TOKEN")
+	os.Unsetenv("API_KEYS")
+	os.Unsetenv("AI_PRICES")
+	os.Unsetenv("AI_DAILY_BUDGET")
+	os.Unsetenv("AI_MONTHLY_BUDGET")
+	os.Unsetenv("AI_KEY_DAILY_BUDGET")
+	os.Unsetenv("AI_KEY_MONTHLY_BUDGET")
+
+	cfg := New()
+
+	assert.NotNil(t, cfg)
+	assert.Equal(t, "8081", cfg.ServerPort)
+	assert.Equal(t, "development", cfg.Environment)
+	assert.Equal(t, "http://localhost:8080/api/v1", cfg.APIBaseURL)
+	assert.Equal(t, 10*time.Second, cfg.APITimeout)
+	assert.Equal(t, "openai", cfg.LLMProvider)
+	assert.Equal(t, 30*time.Second, cfg.LLMTimeout)
+	assert.Equal(t, 400, cfg.LLMMaxTokens)
+	assert.Equal(t, 0.7, cfg.LLMTemperature)
+	assert.Equal(t, "", cfg.OpenAIAPIKey)
+	assert.Equal(t, "https://api.openai.com/v1", cfg.OpenAIBaseURL)
+	assert.Equal(t, "gpt-4o-mini", cfg.OpenAIModel)
+	assert.Equal(t, "http://localhost:11434/v1", cfg.CompatibleBaseURL)
+	assert.Equal(t, "llama3.1", cfg.CompatibleModel)
+	assert.Equal(t, "", cfg.CompatibleAPIKey)
+	assert.Equal(t, "", cfg.CachePath)
+	assert.Equal(t, "", cfg.AdminToken)
+	assert.Empty(t, cfg.APIKeys)
+	assert.Equal(t, Price{Prompt: 0.15, Completion: 0.60}, cfg.AIPrices["gpt-4o-mini"])
+	assert.Equal(t, 0.0, cfg.AIDailyBudget)
+	assert.Equal(t, 0.0, cfg.AIMonthlyBudget)
+	assert.Equal(t, 0.0, cfg.AIKeyDailyBudget)
+	assert.Equal(t, 0.0, cfg.AIKeyMonthlyBudget)
+}
+
+func TestNew_WithEnvironmentVariables(t *testing.T) {
+	// Set environment variables
+	os.Setenv("PORT", "3001")
+	os.Setenv("API_BASE_URL", "http://api:8080/api/v1")
+	os.Setenv("API_TIMEOUT", "2s")
+	os.Setenv("LLM_PROVIDER", "compatible")
+	os.Setenv("LLM_TIMEOUT", "1m")
+	os.Setenv("LLM_MAX_TOKENS", "800")
+	os.Setenv("LLM_TEMPERATURE", "0.2")
+	os.Setenv("OPENAI_API_KEY", "test-key")
+	os.Setenv("OPENAI_BASE_URL", "https://proxy.example.com/v1")
+	os.Setenv("OPENAI_MODEL", "gpt-4o")
+	os.Setenv("LLM_BASE_URL", "http://localhost:8000/v1")
+	os.Setenv("LLM_MODEL", "qwen2.5")
+	os.Setenv("LLM_API_KEY", "local-key")
+	os.Setenv("CACHE_PATH", "data/explanations.db")
+	os.Setenv("ADMIN_TOKEN", "admin-token")
+	os.Setenv("API_KEYS", "key-one, key-two,")
+	os.Setenv("AI_PRICES", "gpt-4o=2.5/10, llama3.1=0/0")
+	os.Setenv("AI_DAILY_BUDGET", "5")
+	os.Setenv("AI_MONTHLY_BUDGET", "100")
+	os.Setenv("AI_KEY_DAILY_BUDGET", "0.5")
+	os.Setenv("AI_KEY_MONTHLY_BUDGET", "10")
+
+	cfg := New()
+
+	assert.Equal(t, "3001", cfg.ServerPort)
+	assert.Equal(t, "http://api:8080/api/v1", cfg.APIBaseURL)
+	assert.Equal(t, 2*time.Second, cfg.APITimeout)
+	assert.Equal(t, "compatible", cfg.LLMProvider)
+	assert.Equal(t, time.Minute, cfg.LLMTimeout)
+	assert.Equal(t, 800, cfg.LLMMaxTokens)
+	assert.Equal(t, 0.2, cfg.LLMTemperature)
+	assert.Equal(t, "test-key", cfg.OpenAIAPIKey)
+	assert.Equal(t, "https://proxy.example.com/v1", cfg.OpenAIBaseURL)
+	assert.Equal(t, "gpt-4o", cfg.OpenAIModel)
+	assert.Equal(t, "http://localhost:8000/v1", cfg.CompatibleBaseURL)
+	assert.Equal(t, "qwen2.5", cfg.CompatibleModel)
+	assert.Equal(t, "local-key", cfg.CompatibleAPIKey)
+	assert.Equal(t, "data/explanations.db", cfg.CachePath)
+	assert.Equal(t, "admin-token", cfg.AdminToken)
+	assert.Equal(t, []string{"key-one", "key-two"}, cfg.APIKeys)
+	assert.Equal(t, map[string]Price{"gpt-4o": {Prompt: 2.5, Completion: 10}, "llama3.1": {}}, cfg.AIPrices)
+	assert.Equal(t, 5.0, cfg.AIDailyBudget)
+	assert.Equal(t, 100.0, cfg.AIMonthlyBudget)
+	assert.Equal(t, 0.5, cfg.AIKeyDailyBudget)
+	assert.Equal(t, 10.0, cfg.AIKeyMonthlyBudget)
+
+	// Clean up
+	os.Unsetenv("PORT")
+	os.Unsetenv("API_BASE_URL")
+	os.Unsetenv("API_TIMEOUT")
+	os.Unsetenv("LLM_PROVIDER")
+	os.Unsetenv("LLM_TIMEOUT")
+	os.Unsetenv("LLM_MAX_TOKENS")
+	os.Unsetenv("LLM_TEMPERATURE")
+	os.Unsetenv("OPENAI_API_KEY")
+	os.Unsetenv("OPENAI_BASE_URL")
+	os.Unsetenv("OPENAI_MODEL")
+	os.Unsetenv("LLM_BASE_URL")
+	os.Unsetenv("LLM_MODEL")
+	os.Unsetenv("LLM_API_KEY")
+	os.Unsetenv("CACHE_PATH")
+	os.Unsetenv("ADMIN_TOKEN")
+	os.Unsetenv("API_KEYS")
+	os.Unsetenv("AI_PRICES")
+	os.Unsetenv("AI_DAILY_BUDGET")
+	os.Unsetenv("AI_MONTHLY_BUDGET")
+	os.Unsetenv("AI_KEY_DAILY_BUDGET")
+	os.Unsetenv("AI_KEY_MONTHLY_BUDGET")
+}
+
+func TestNew_InvalidPrices(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	for _, value := range []string{"gpt-4o", "gpt-4o=2.5", "gpt-4o=cheap/10", "=1/2"} {
+		os.Setenv("AI_PRICES", value)
+		logs.Reset()
+
+		assert.Equal(t, defaultPrices, New().AIPrices, value)
+		assert.Contains(t, logs.String(), "Invalid AI_PRICES", value)
+	}
+
+	os.Unsetenv("AI_PRICES")
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/types"
+)
+
+// AdminHandler represents the handler for administrative endpoints
+type AdminHandler struct {
+	explanations *services.ExplanationService
+	usage        *services.UsageTracker
+}
+
+// NewAdminHandler creates a new instance of the handler
+func NewAdminHandler(explanations *services.ExplanationService, usage *services.UsageTracker) *AdminHandler {
+	return &AdminHandler{
+		explanations: explanations,
+		usage:        usage,
+	}
+}
+
+// GetUsage returns the AI usage and budgets of the current day and month
+func (h *AdminHandler) GetUsage(c *gin.Context) {
+	respondData(c, h.usage.Report())
+}
+
+// RegenerateExplanation generates the explanation of a Pokémon again,
+// replacing the stored one
+func (h *AdminHandler) RegenerateExplanation(c *gin.Context) {
+	var request types.ExplanationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest)
+		return
+	}
+
+	if request.PokemonID < 1 {
+		respondError(c, http.StatusBadRequest, CodeInvalidID)
+		return
+	}
+
+	explanation, err := h.explanations.Regenerate(c.Request.Context(), request.PokemonID, request.Language, request.Persona)
+	if err != nil {
+		log.Printf("Error regenerating the explanation of Pokémon %d: %v", request.PokemonID, err)
+		respondServiceError(c, err)
+		return
+	}
+
+	respondData(c, explanation)
+}
+
+// RequireAdminToken rejects the requests that do not send the admin token
+// as a bearer token in the Authorization header
+func RequireAdminToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+
+	return func(c *gin.Context) {
+		authorization := []byte(strings.TrimSpace(c.GetHeader("Authorization")))
+		if token == "" || subtle.ConstantTimeCompare(authorization, expected) != 1 {
+			respondError(c, http.StatusUnauthorized, CodeUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
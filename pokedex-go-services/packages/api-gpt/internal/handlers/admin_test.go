@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/middleware"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/store"
+	"pokedexia-gpt/internal/types"
+)
+
+func TestRegenerateExplanation(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "explanations.db"))
+	assert.NoError(t, err)
+	defer st.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	pokemon := services.NewPokemonClient(&config.Config{APIBaseURL: api.URL})
+	explanations := services.NewExplanationService(pokemon, services.NewTemplateProvider()).WithStore(st)
+	explanationHandler := NewExplanationHandler(explanations, services.NewTemplateProvider())
+	adminHandler := NewAdminHandler(explanations, nil)
+
+	router.POST("/explanations", explanationHandler.CreateExplanation)
+	router.POST("/admin/explanations/regenerate", RequireAdminToken("admin-token"), adminHandler.RegenerateExplanation)
+
+	regenerate := func(authorization, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin/explanations/regenerate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	var response struct {
+		Data types.AIExplanation `json:"data"`
+	}
+
+	// The first request stores the explanation
+	w := postExplanation(router, `{"pokemon_id": 25}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Data.Cached)
+
+	w = postExplanation(router, `{"pokemon_id": 25}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Data.Cached)
+
+	// Regenerating replaces it
+	w = regenerate("Bearer admin-token", `{"pokemon_id": 25, "language": "en", "persona": "general"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Data.Cached)
+	assert.Equal(t, "general", response.Data.Persona)
+	assert.NotEmpty(t, response.Data.PromptVersion)
+
+	regenerated := response.Data
+
+	w = postExplanation(router, `{"pokemon_id": 25}`)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Data.Cached)
+	assert.Equal(t, regenerated.GeneratedAt, response.Data.GeneratedAt)
+
+	// Errors
+	testCases := []struct {
+		name          string
+		authorization string
+		body          string
+		status        int
+		code          string
+	}{
+		{"no token", "", `{"pokemon_id": 25}`, http.StatusUnauthorized, CodeUnauthorized},
+		{"wrong token", "Bearer other-token", `{"pokemon_id": 25}`, http.StatusUnauthorized, CodeUnauthorized},
+		{"invalid body", "Bearer admin-token", `{"pokemon_id": "pikachu"}`, http.StatusBadRequest, CodeInvalidRequest},
+		{"invalid ID", "Bearer admin-token", `{"pokemon_id": 0}`, http.StatusBadRequest, CodeInvalidID},
+		{"invalid persona", "Bearer admin-token", `{"pokemon_id": 25, "persona": "pirate"}`, http.StatusBadRequest, CodeInvalidPersona},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := regenerate(tc.authorization, tc.body)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var response types.Envelope
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.NotNil(t, response.Error) {
+				assert.Equal(t, tc.code, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestRequireAdminToken_Empty(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", RequireAdminToken(""), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// An empty token never matches
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetUsage(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.IdentifyClient([]string{"client-key"}))
+
+	cfg := &config.Config{
+		APIBaseURL:       api.URL,
+		OpenAIAPIKey:     "test-key",
+		OpenAIBaseURL:    openAI.URL,
+		OpenAIModel:      "gpt-4o-mini",
+		AIPrices:         map[string]config.Price{"gpt-4o-mini": {Prompt: 0.15, Completion: 0.60}},
+		AIKeyDailyBudget: 0.00001,
+	}
+	provider := services.NewOpenAIProvider(cfg)
+	usage := services.NewUsageTracker(cfg)
+	explanations := services.NewExplanationService(services.NewPokemonClient(cfg), provider).WithUsage(usage)
+	explanationHandler := NewExplanationHandler(explanations, provider)
+	adminHandler := NewAdminHandler(explanations, usage)
+
+	router.POST("/explanations", explanationHandler.CreateExplanation)
+	router.POST("/admin/explanations/regenerate", adminHandler.RegenerateExplanation)
+	router.GET("/admin/ai/usage", adminHandler.GetUsage)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/explanations", bytes.NewBufferString(`{"pokemon_id": 25}`))
+	req.Header.Set(middleware.APIKeyHeader, "client-key")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/ai/usage", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data types.UsageReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "USD", response.Data.Currency)
+	assert.Equal(t, 1, response.Data.Day.Total.Requests)
+	assert.Equal(t, 0.00001, response.Data.Day.KeyBudget)
+	assert.Equal(t, 1, response.Data.Month.Clients[services.ClientID("client-key")].Requests)
+	assert.NotContains(t, w.Body.String(), "client-key")
+
+	// Spend the budget of the anonymous client
+	usage.Record(services.AnonymousClient, "gpt-4o-mini", types.Usage{PromptTokens: 1000, CompletionTokens: 1000})
+
+	w = postExplanation(router, `{"pokemon_id": 25}`)
+	var explanation struct {
+		Data types.AIExplanation `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &explanation))
+	assert.True(t, explanation.Data.Degraded)
+	assert.Equal(t, services.NewTemplateProvider().Model(), explanation.Data.Model)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/admin/explanations/regenerate", bytes.NewBufferString(`{"pokemon_id": 25}`))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	var errorResponse types.Envelope
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errorResponse))
+	assert.Equal(t, CodeBudgetExhausted, errorResponse.Error.Code)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/types"
+)
+
+// Error codes. They are part of the API contract and never change.
+const (
+	CodeInvalidRequest          = "INVALID_REQUEST"
+	CodeInvalidID               = "INVALID_ID"
+	CodeInvalidPersona          = "INVALID_PERSONA"
+	CodeUnsupportedLanguage     = "UNSUPPORTED_LANGUAGE"
+	CodeUnauthorized            = "UNAUTHORIZED"
+	CodePokemonNotFound         = "POKEMON_NOT_FOUND"
+	CodeAINotConfigured         = "AI_NOT_CONFIGURED"
+	CodeUpstreamTimeout         = "UPSTREAM_TIMEOUT"
+	CodeUpstreamRateLimited     = "UPSTREAM_RATE_LIMITED"
+	CodeBudgetExhausted         = "BUDGET_EXHAUSTED"
+	CodeUpstreamUnavailable     = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamInvalidResponse = "UPSTREAM_INVALID_RESPONSE"
+	CodeInternalError           = "INTERNAL_ERROR"
+)
+
+// messages holds the message of each error code
+var messages = map[string]string{
+	CodeInvalidRequest:          "The request body must be a JSON object with a pokemon_id",
+	CodeInvalidID:               "The Pokémon ID must be a positive number",
+	CodeInvalidPersona:          "persona must be general, kids or competitive",
+	CodeUnsupportedLanguage:     "The language is not supported",
+	CodeUnauthorized:            "A valid admin token is required",
+	CodePokemonNotFound:         "Pokémon not found",
+	CodeAINotConfigured:         "The AI provider is not configured",
+	CodeUpstreamTimeout:         "An upstream service took too long to respond, please try again later",
+	CodeUpstreamRateLimited:     "An upstream service rate limit was exceeded, please try again later",
+	CodeBudgetExhausted:         "The AI budget is exhausted, please try again later",
+	CodeUpstreamUnavailable:     "An upstream service is unavailable at the moment, please try again later",
+	CodeUpstreamInvalidResponse: "An upstream service returned an invalid response",
+	CodeInternalError:           "Error generating the explanation",
+}
+
+// statusClientClosedRequest is used when the client disconnects before the response is ready
+const statusClientClosedRequest = 499
+
+// respondServiceError maps an error from the services to the matching HTTP response
+func respondServiceError(c *gin.Context, err error) {
+	// Nobody is waiting for the response anymore
+	if errors.Is(err, context.Canceled) {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+
+	status, body := serviceError(err)
+	c.JSON(status, types.Envelope{Error: body})
+}
+
+// serviceError returns the HTTP status and the error body matching an error from the services
+func serviceError(err error) (int, *types.ErrorBody) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, newErrorBody(CodeUpstreamTimeout)
+	case errors.Is(err, services.ErrUnknownPersona):
+		return http.StatusBadRequest, newErrorBody(CodeInvalidPersona)
+	case errors.Is(err, services.ErrUnsupportedLanguage):
+		return http.StatusBadRequest, newErrorBody(CodeUnsupportedLanguage)
+	case errors.Is(err, services.ErrNotFound):
+		return http.StatusNotFound, newErrorBody(CodePokemonNotFound)
+	case errors.Is(err, services.ErrInvalidRequest):
+		// Pass on the error of the Pokémon API, which is already in the client's language
+		var upstreamErr *services.UpstreamError
+		if errors.As(err, &upstreamErr) && upstreamErr.Code != "" {
+			return http.StatusBadRequest, &types.ErrorBody{Code: upstreamErr.Code, Message: upstreamErr.Message}
+		}
+		return http.StatusBadRequest, newErrorBody(CodeInvalidRequest)
+	case errors.Is(err, services.ErrNotConfigured):
+		return http.StatusServiceUnavailable, newErrorBody(CodeAINotConfigured)
+	case errors.Is(err, services.ErrBudgetExhausted):
+		return http.StatusTooManyRequests, newErrorBody(CodeBudgetExhausted)
+	case errors.Is(err, services.ErrRateLimited):
+		return http.StatusTooManyRequests, newErrorBody(CodeUpstreamRateLimited)
+	case errors.Is(err, services.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, newErrorBody(CodeUpstreamUnavailable)
+	case errors.Is(err, services.ErrDecode):
+		return http.StatusBadGateway, newErrorBody(CodeUpstreamInvalidResponse)
+	default:
+		return http.StatusInternalServerError, newErrorBody(CodeInternalError)
+	}
+}
+
+// respondError writes an error response with a stable code and its message
+func respondError(c *gin.Context, status int, code string) {
+	c.JSON(status, types.Envelope{Error: newErrorBody(code)})
+}
+
+// newErrorBody returns the error body of a stable code with its message
+func newErrorBody(code string) *types.ErrorBody {
+	return &types.ErrorBody{
+		Code:    code,
+		Message: messages[code],
+	}
+}
+
+// respondData writes a successful response with the data
+func respondData(c *gin.Context, data any) {
+	c.JSON(http.StatusOK, types.Envelope{Success: true, Data: data})
+}
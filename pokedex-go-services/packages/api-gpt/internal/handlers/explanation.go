@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/types"
+)
+
+// Events of the explanation stream
+const (
+	eventToken = "token"
+	eventDone  = "done"
+	eventError = "error"
+)
+
+// ExplanationHandler represents the handler for explanation endpoints
+type ExplanationHandler struct {
+	explanations *services.ExplanationService
+	provider     services.LLMProvider
+}
+
+// NewExplanationHandler creates a new instance of the handler
+func NewExplanationHandler(explanations *services.ExplanationService, provider services.LLMProvider) *ExplanationHandler {
+	return &ExplanationHandler{
+		explanations: explanations,
+		provider:     provider,
+	}
+}
+
+// CreateExplanation generates the explanation of a Pokémon
+func (h *ExplanationHandler) CreateExplanation(c *gin.Context) {
+	var request types.ExplanationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, http.StatusBadRequest, CodeInvalidRequest)
+		return
+	}
+
+	if request.PokemonID < 1 {
+		respondError(c, http.StatusBadRequest, CodeInvalidID)
+		return
+	}
+
+	explanation, err := h.explanations.Explain(c.Request.Context(), request.PokemonID, request.Language, request.Persona)
+	if err != nil {
+		log.Printf("Error explaining Pokémon %d: %v", request.PokemonID, err)
+		respondServiceError(c, err)
+		return
+	}
+
+	respondData(c, explanation)
+}
+
+// StreamExplanation generates the explanation of a Pokémon and sends it as
+// Server-Sent Events: a "token" event with each piece of text as the provider
+// writes it, then a "done" event with the whole explanation. Errors before the
+// first event get a regular error response, later ones an "error" event.
+func (h *ExplanationHandler) StreamExplanation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		respondError(c, http.StatusBadRequest, CodeInvalidID)
+		return
+	}
+
+	ctx := c.Request.Context()
+	explanation, err := h.explanations.ExplainStream(ctx, id, c.Query("lang"), c.Query("persona"), func(token string) error {
+		// Stop generating once the client has gone
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		sendEvent(c, eventToken, types.ExplanationToken{Text: token})
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		log.Printf("Client disconnected from the explanation stream of Pokémon %d", id)
+		if !c.Writer.Written() {
+			c.AbortWithStatus(statusClientClosedRequest)
+		}
+	case err != nil:
+		log.Printf("Error streaming the explanation of Pokémon %d: %v", id, err)
+		if !c.Writer.Written() {
+			respondServiceError(c, err)
+			return
+		}
+		_, body := serviceError(err)
+		sendEvent(c, eventError, body)
+	default:
+		sendEvent(c, eventDone, explanation)
+	}
+}
+
+// HealthCheck checks if the API is working
+func (h *ExplanationHandler) HealthCheck(c *gin.Context) {
+	status := "ok"
+	if !h.provider.Configured() {
+		status = "degraded"
+	}
+
+	respondData(c, gin.H{
+		"status":  status,
+		"message": "PokedexIA GPT API is running",
+		"ai": gin.H{
+			"provider":   h.provider.Name(),
+			"model":      h.provider.Model(),
+			"configured": h.provider.Configured(),
+		},
+	})
+}
+
+// sendEvent writes a Server-Sent Event with the data as JSON and flushes it
+// to the client, starting the stream on the first event
+func sendEvent(c *gin.Context, event string, data any) {
+	if !c.Writer.Written() {
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		// Keep proxies such as nginx from buffering the stream
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+	}
+
+	c.SSEvent(event, data)
+	c.Writer.Flush()
+}
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/types"
+)
+
+// newFakeServers starts a local Pokémon API serving Pikachu and a local
+// OpenAI-compatible server answering with the status
+func newFakeServers(openAIStatus int) (api, openAI *httptest.Server) {
+	api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon/id/25":
+			fmt.Fprint(w, `{"success": true, "data": {"id": 25, "name": "pikachu", "types": ["electric"]}}`)
+		case "/pokemon/id/25/species":
+			fmt.Fprint(w, `{"success": true, "data": {"id": 25, "name": "pikachu"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"success": false, "error": {"code": "POKEMON_NOT_FOUND", "message": "Pokémon not found"}}`)
+		}
+	}))
+
+	openAI = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if openAIStatus != http.StatusOK {
+			w.WriteHeader(openAIStatus)
+			fmt.Fprint(w, `{"error": {"message": "The server is overloaded"}}`)
+			return
+		}
+
+		var request types.ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Stream {
+			for _, word := range []string{"Pikachu ", "is ", "an ", "Electric-type ", "Pokémon."} {
+				fmt.Fprintf(w, "data: {\"model\": \"gpt-4o-mini\", \"choices\": [{\"delta\": {\"content\": %q}}]}\n\n", word)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		fmt.Fprint(w, `{"model": "gpt-4o-mini", "choices": [{"message": {"role": "assistant", "content": "Pikachu is an Electric-type Pokémon."}}]}`)
+	}))
+
+	return api, openAI
+}
+
+func setupTestRouter(api, openAI, key string) *gin.Engine {
+	cfg := &config.Config{
+		OpenAIAPIKey:  key,
+		OpenAIBaseURL: openAI,
+		OpenAIModel:   "gpt-4o-mini",
+	}
+	return setupProviderRouter(api, services.NewOpenAIProvider(cfg))
+}
+
+func setupProviderRouter(api string, provider services.LLMProvider) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	pokemon := services.NewPokemonClient(&config.Config{APIBaseURL: api})
+	handler := NewExplanationHandler(services.NewExplanationService(pokemon, provider), provider)
+
+	router.GET("/health", handler.HealthCheck)
+	router.POST("/explanations", handler.CreateExplanation)
+	router.GET("/pokemon/id/:id/explanation/stream", handler.StreamExplanation)
+	return router
+}
+
+// sseEvent represents a Server-Sent Event read from a response
+type sseEvent struct {
+	Event string
+	Data  string
+}
+
+// readEvents parses the Server-Sent Events of a response body
+func readEvents(body string) []sseEvent {
+	var events []sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		var event sseEvent
+		for _, line := range strings.Split(block, "\n") {
+			field, value, _ := strings.Cut(line, ":")
+			switch field {
+			case "event":
+				event.Event = strings.TrimSpace(value)
+			case "data":
+				event.Data = strings.TrimSpace(value)
+			}
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func getStream(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func postExplanation(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/explanations", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateExplanation(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	router := setupTestRouter(api.URL, openAI.URL, "test-key")
+
+	w := postExplanation(router, `{"pokemon_id": 25, "language": "en"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                `json:"success"`
+		Data    types.AIExplanation `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Success)
+	assert.Equal(t, 25, response.Data.PokemonID)
+	assert.Equal(t, "pikachu", response.Data.Name)
+	assert.Equal(t, "en", response.Data.Language)
+	assert.Equal(t, "Pikachu is an Electric-type Pokémon.", response.Data.Explanation)
+	assert.Equal(t, "gpt-4o-mini", response.Data.Model)
+	assert.NotEmpty(t, response.Data.GeneratedAt)
+}
+
+func TestCreateExplanation_TemplateProvider(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	router := setupProviderRouter(api.URL, services.NewTemplateProvider())
+
+	w := postExplanation(router, `{"pokemon_id": 25}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data types.AIExplanation `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, services.NewTemplateProvider().Model(), response.Data.Model)
+	assert.Contains(t, response.Data.Explanation, "pikachu is Pokémon #25")
+}
+
+func TestCreateExplanation_Errors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		body         string
+		key          string
+		openAIStatus int
+		status       int
+		code         string
+	}{
+		{"invalid body", `{"pokemon_id": "pikachu"}`, "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidRequest},
+		{"missing ID", `{}`, "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidID},
+		{"negative ID", `{"pokemon_id": -1}`, "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidID},
+		{"invalid persona", `{"pokemon_id": 25, "persona": "pirate"}`, "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidPersona},
+		{"unsupported language", `{"pokemon_id": 25, "language": "eo"}`, "test-key", http.StatusOK, http.StatusBadRequest, CodeUnsupportedLanguage},
+		{"not found", `{"pokemon_id": 150}`, "test-key", http.StatusOK, http.StatusNotFound, CodePokemonNotFound},
+		{"not configured", `{"pokemon_id": 25}`, "", http.StatusOK, http.StatusServiceUnavailable, CodeAINotConfigured},
+		{"AI unavailable", `{"pokemon_id": 25}`, "test-key", http.StatusServiceUnavailable, http.StatusServiceUnavailable, CodeUpstreamUnavailable},
+		{"AI rate limited", `{"pokemon_id": 25}`, "test-key", http.StatusTooManyRequests, http.StatusTooManyRequests, CodeUpstreamRateLimited},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, openAI := newFakeServers(tc.openAIStatus)
+			defer api.Close()
+			defer openAI.Close()
+
+			router := setupTestRouter(api.URL, openAI.URL, tc.key)
+
+			w := postExplanation(router, tc.body)
+
+			assert.Equal(t, tc.status, w.Code)
+
+			var response types.Envelope
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			if assert.NotNil(t, response.Error) {
+				assert.Equal(t, tc.code, response.Error.Code)
+				assert.Equal(t, messages[tc.code], response.Error.Message)
+			}
+		})
+	}
+}
+
+func TestCreateExplanation_APIError(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"success": false, "error": {"code": "UNSUPPORTED_LANGUAGE", "message": "Idioma não suportado: ko"}}`)
+	}))
+	defer api.Close()
+
+	router := setupTestRouter(api.URL, "http://localhost:0", "test-key")
+
+	w := postExplanation(router, `{"pokemon_id": 25, "language": "ko"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response types.Envelope
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "UNSUPPORTED_LANGUAGE", response.Error.Code)
+	assert.Equal(t, "Idioma não suportado: ko", response.Error.Message)
+}
+
+func TestStreamExplanation(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	router := setupTestRouter(api.URL, openAI.URL, "test-key")
+
+	w := getStream(router, "/pokemon/id/25/explanation/stream?lang=en")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	events := readEvents(w.Body.String())
+	if !assert.Len(t, events, 6) {
+		return
+	}
+
+	var text strings.Builder
+	for _, event := range events[:5] {
+		assert.Equal(t, eventToken, event.Event)
+
+		var token types.ExplanationToken
+		assert.NoError(t, json.Unmarshal([]byte(event.Data), &token))
+		text.WriteString(token.Text)
+	}
+	assert.Equal(t, "Pikachu is an Electric-type Pokémon.", text.String())
+
+	// The last event carries the whole explanation
+	assert.Equal(t, eventDone, events[5].Event)
+	var explanation types.AIExplanation
+	assert.NoError(t, json.Unmarshal([]byte(events[5].Data), &explanation))
+	assert.Equal(t, 25, explanation.PokemonID)
+	assert.Equal(t, "en", explanation.Language)
+	assert.Equal(t, text.String(), explanation.Explanation)
+	assert.Equal(t, "gpt-4o-mini", explanation.Model)
+	assert.NotEmpty(t, explanation.GeneratedAt)
+}
+
+func TestStreamExplanation_TemplateProvider(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	router := setupProviderRouter(api.URL, services.NewTemplateProvider())
+
+	w := getStream(router, "/pokemon/id/25/explanation/stream")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	events := readEvents(w.Body.String())
+	assert.Equal(t, eventDone, events[len(events)-1].Event)
+	assert.Contains(t, events[len(events)-1].Data, `"model":"`+services.NewTemplateProvider().Model()+`"`)
+}
+
+func TestStreamExplanation_Errors(t *testing.T) {
+	testCases := []struct {
+		name         string
+		path         string
+		key          string
+		openAIStatus int
+		status       int
+		code         string
+	}{
+		{"invalid ID", "/pokemon/id/pikachu/explanation/stream", "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidID},
+		{"zero ID", "/pokemon/id/0/explanation/stream", "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidID},
+		{"invalid persona", "/pokemon/id/25/explanation/stream?persona=pirate", "test-key", http.StatusOK, http.StatusBadRequest, CodeInvalidPersona},
+		{"unsupported language", "/pokemon/id/25/explanation/stream?lang=eo", "test-key", http.StatusOK, http.StatusBadRequest, CodeUnsupportedLanguage},
+		{"not found", "/pokemon/id/150/explanation/stream", "test-key", http.StatusOK, http.StatusNotFound, CodePokemonNotFound},
+		{"not configured", "/pokemon/id/25/explanation/stream", "", http.StatusOK, http.StatusServiceUnavailable, CodeAINotConfigured},
+		{"AI rate limited", "/pokemon/id/25/explanation/stream", "test-key", http.StatusTooManyRequests, http.StatusTooManyRequests, CodeUpstreamRateLimited},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, openAI := newFakeServers(tc.openAIStatus)
+			defer api.Close()
+			defer openAI.Close()
+
+			router := setupTestRouter(api.URL, openAI.URL, tc.key)
+
+			w := getStream(router, tc.path)
+
+			// Errors before the stream starts are regular responses
+			assert.Equal(t, tc.status, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+			var response types.Envelope
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.NotNil(t, response.Error) {
+				assert.Equal(t, tc.code, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestStreamExplanation_ErrorEvent(t *testing.T) {
+	api, unused := newFakeServers(http.StatusOK)
+	defer api.Close()
+	unused.Close()
+
+	// The provider fails after the first token
+	openAI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Pikachu \"}}]}\n\ndata: {\"choices\n\n")
+	}))
+	defer openAI.Close()
+
+	router := setupTestRouter(api.URL, openAI.URL, "test-key")
+
+	w := getStream(router, "/pokemon/id/25/explanation/stream")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	events := readEvents(w.Body.String())
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, eventToken, events[0].Event)
+		assert.Equal(t, eventError, events[1].Event)
+
+		var body types.ErrorBody
+		assert.NoError(t, json.Unmarshal([]byte(events[1].Data), &body))
+		assert.Equal(t, CodeUpstreamInvalidResponse, body.Code)
+		assert.Equal(t, messages[CodeUpstreamInvalidResponse], body.Message)
+	}
+}
+
+func TestStreamExplanation_ClientGone(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	router := setupProviderRouter(api.URL, services.NewTemplateProvider())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/pokemon/id/25/explanation/stream", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, statusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+// disconnectingProvider streams one token, then simulates the client
+// disconnecting before the next one
+type disconnectingProvider struct {
+	services.TemplateProvider
+	disconnect context.CancelFunc
+}
+
+func (p *disconnectingProvider) Stream(ctx context.Context, prompt services.Prompt, onToken services.TokenFunc) (*services.Completion, error) {
+	if err := onToken("Pikachu "); err != nil {
+		return nil, err
+	}
+	p.disconnect()
+	if err := onToken("is "); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("expected the stream to stop")
+}
+
+func TestStreamExplanation_ClientDisconnects(t *testing.T) {
+	api, openAI := newFakeServers(http.StatusOK)
+	defer api.Close()
+	defer openAI.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	router := setupProviderRouter(api.URL, &disconnectingProvider{disconnect: cancel})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/pokemon/id/25/explanation/stream", nil)
+	router.ServeHTTP(w, req)
+
+	// The stream ends after the token sent before the disconnect
+	events := readEvents(w.Body.String())
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, eventToken, events[0].Event)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	testCases := []struct {
+		key    string
+		status string
+	}{
+		{"test-key", "ok"},
+		{"", "degraded"},
+	}
+
+	for _, tc := range testCases {
+		router := setupTestRouter("http://localhost:0", "http://localhost:0", tc.key)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/health", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data struct {
+				Status string `json:"status"`
+				AI     struct {
+					Provider   string `json:"provider"`
+					Model      string `json:"model"`
+					Configured bool   `json:"configured"`
+				} `json:"ai"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, tc.status, response.Data.Status)
+		assert.Equal(t, "openai", response.Data.AI.Provider)
+		assert.Equal(t, "gpt-4o-mini", response.Data.AI.Model)
+		assert.Equal(t, tc.key != "", response.Data.AI.Configured)
+	}
+}
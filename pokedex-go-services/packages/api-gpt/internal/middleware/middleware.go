@@ -0,0 +1,33 @@
+// Package middleware holds the Gin middleware shared by every route.
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"pokedexia-gpt/internal/services"
+)
+
+// APIKeyHeader is the header that identifies the client
+const APIKeyHeader = "X-API-Key"
+
+// IdentifyClient records the client of each request from its API key, so
+// AI usage is accounted and budgeted per client. Only the configured keys
+// identify a client; requests with another key or none share the anonymous
+// client, so made-up keys get no budget of their own.
+func IdentifyClient(apiKeys []string) gin.HandlerFunc {
+	// Keys are compared by their hash, so the lookup does not leak them
+	clients := make(map[string]bool, len(apiKeys))
+	for _, key := range apiKeys {
+		clients[services.ClientID(key)] = true
+	}
+
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader(APIKeyHeader)
+		if !clients[services.ClientID(apiKey)] {
+			apiKey = ""
+		}
+
+		ctx := services.WithClient(c.Request.Context(), apiKey)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
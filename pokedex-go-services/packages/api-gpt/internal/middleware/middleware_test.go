@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pokedexia-gpt/internal/services"
+)
+
+func TestIdentifyClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(IdentifyClient([]string{"secret-key"}))
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, services.ClientFrom(c.Request.Context()))
+	})
+
+	testCases := []struct {
+		name   string
+		apiKey string
+		client string
+	}{
+		{"without key", "", services.AnonymousClient},
+		{"with key", "secret-key", services.ClientID("secret-key")},
+		{"with unknown key", "secret-made-up-key", services.AnonymousClient},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tc.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tc.apiKey)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.client, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "secret")
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"pokedexia-gpt/internal/store"
+	"pokedexia-gpt/internal/types"
+)
+
+// CacheKey identifies a stored explanation. An explanation is only reused
+// for the same Pokémon, language, persona, prompt version and model, so
+// changing the prompt or the model leaves the old entries behind.
+type CacheKey struct {
+	PokemonID     int
+	Language      string
+	Persona       string
+	PromptVersion string
+	Model         string
+}
+
+// String returns the store key
+func (k CacheKey) String() string {
+	return fmt.Sprintf("explanations/%d/%s/%s/%s/%s", k.PokemonID, k.Language, k.Persona, k.PromptVersion, k.Model)
+}
+
+// readStore returns the explanation stored under the key, or nil when there
+// is none. Failures are logged and treated as a miss.
+func (s *ExplanationService) readStore(key CacheKey) *types.AIExplanation {
+	if s.store == nil {
+		return nil
+	}
+
+	body, err := s.store.Get(key.String())
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Error reading %s from the store: %v", key, err)
+		}
+		return nil
+	}
+
+	var explanation types.AIExplanation
+	if err := json.Unmarshal(body, &explanation); err != nil {
+		log.Printf("Error decoding %s from the store: %v", key, err)
+		return nil
+	}
+
+	explanation.Cached = true
+	return &explanation
+}
+
+// persist writes the explanation to the store under the key. Failures are
+// logged and never fail the request.
+func (s *ExplanationService) persist(key CacheKey, explanation *types.AIExplanation) {
+	if s.store == nil {
+		return
+	}
+
+	body, err := json.Marshal(explanation)
+	if err != nil {
+		log.Printf("Error encoding %s for the store: %v", key, err)
+		return
+	}
+
+	if err := s.store.Put(key.String(), body); err != nil {
+		log.Printf("Error writing %s to the store: %v", key, err)
+	}
+}
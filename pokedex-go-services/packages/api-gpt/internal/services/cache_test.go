@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pokedexia-gpt/internal/store"
+)
+
+// mapStore is an in-memory store.Store used by the tests
+type mapStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{values: make(map[string][]byte)}
+}
+
+func (s *mapStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *mapStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+func (s *mapStore) Close() error {
+	return nil
+}
+
+func TestCacheKey_String(t *testing.T) {
+	key := CacheKey{PokemonID: 25, Language: "pt-BR", Persona: PersonaKids, PromptVersion: "0123456789ab", Model: "gpt-4o-mini"}
+
+	if key.String() != "explanations/25/pt-BR/kids/0123456789ab/gpt-4o-mini" {
+		t.Errorf("Unexpected key %s", key)
+	}
+}
+
+func TestExplain_Store(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	st := newMapStore()
+	service := newTestExplanationService(fake, api.URL).WithStore(st)
+
+	first, err := service.Explain(context.Background(), 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first.Cached {
+		t.Error("Expected a new explanation to not be cached")
+	}
+
+	key := CacheKey{PokemonID: 25, Language: "en", Persona: DefaultPersona, PromptVersion: promptVersions[DefaultPersona], Model: "gpt-4o-mini"}
+	if _, err := st.Get(key.String()); err != nil {
+		t.Fatalf("Expected the explanation under %s, got %v", key, err)
+	}
+
+	// The same request is answered from the store
+	second, err := service.Explain(context.Background(), 25, "en", PersonaGeneral)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !second.Cached || second.Explanation != first.Explanation || second.GeneratedAt != first.GeneratedAt {
+		t.Errorf("Expected the stored explanation, got %+v", second)
+	}
+	if len(fake.requests) != 1 {
+		t.Errorf("Expected 1 completion, got %d", len(fake.requests))
+	}
+
+	// Another language or persona is another explanation
+	if _, err := service.Explain(context.Background(), 25, "es", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	kids, err := service.Explain(context.Background(), 25, "en", PersonaKids)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if kids.Cached || kids.Persona != PersonaKids || len(fake.requests) != 3 {
+		t.Errorf("Expected a new explanation for each language and persona, got %d completions", len(fake.requests))
+	}
+}
+
+func TestExplain_StoreIgnoresOldPrompts(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	// An explanation generated with an earlier version of the prompt
+	st := newMapStore()
+	old := CacheKey{PokemonID: 25, Language: "en", Persona: DefaultPersona, PromptVersion: "000000000000", Model: "gpt-4o-mini"}
+	st.Put(old.String(), []byte(`{"pokemon_id": 25, "explanation": "Outdated explanation."}`))
+
+	service := newTestExplanationService(fake, api.URL).WithStore(st)
+
+	explanation, err := service.Explain(context.Background(), 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explanation.Cached || explanation.Explanation == "Outdated explanation." {
+		t.Errorf("Expected a new explanation, got %+v", explanation)
+	}
+}
+
+func TestExplainStream_Stored(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL).WithStore(newMapStore())
+	if _, err := service.Explain(context.Background(), 25, "en", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A stored explanation is sent as a single token
+	var tokens []string
+	explanation, err := service.ExplainStream(context.Background(), 25, "en", "", func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !explanation.Cached || len(tokens) != 1 || tokens[0] != explanation.Explanation {
+		t.Errorf("Expected the stored explanation as one token, got %q", tokens)
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL).WithStore(newMapStore())
+	if _, err := service.Explain(context.Background(), 25, "en", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	service.now = func() time.Time {
+		return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	}
+	regenerated, err := service.Regenerate(context.Background(), 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if regenerated.Cached || regenerated.GeneratedAt != "2024-06-01T12:00:00Z" || len(fake.requests) != 2 {
+		t.Errorf("Expected a new explanation, got %+v", regenerated)
+	}
+
+	// The regenerated explanation replaces the stored one
+	explanation, _ := service.Explain(context.Background(), 25, "en", "")
+	if !explanation.Cached || explanation.GeneratedAt != regenerated.GeneratedAt {
+		t.Errorf("Expected the regenerated explanation, got %+v", explanation)
+	}
+}
+
+func TestExplain_UnknownPersona(t *testing.T) {
+	fake := newFakeOpenAI("")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	if _, err := service.Explain(context.Background(), 25, "en", "pirate"); !errors.Is(err, ErrUnknownPersona) {
+		t.Errorf("Expected ErrUnknownPersona, got %v", err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected no completion, got %d", len(fake.requests))
+	}
+}
+
+func TestExplain_StoreNormalizesLanguage(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu armazena eletricidade nas bochechas.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL).WithStore(newMapStore())
+
+	// Every spelling of the language shares the stored explanation
+	for _, lang := range []string{"pt-BR", "pt-br", "PT", "pt_PT"} {
+		explanation, err := service.Explain(context.Background(), 25, lang, "")
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", lang, err)
+		}
+		if explanation.Language != "pt-BR" {
+			t.Errorf("%s: expected the language pt-BR, got %s", lang, explanation.Language)
+		}
+	}
+	if len(fake.requests) != 1 {
+		t.Errorf("Expected 1 completion, got %d", len(fake.requests))
+	}
+	if prompt := fake.requests[0].Messages[1].Content; !strings.Contains(prompt, "in Brazilian Portuguese") {
+		t.Errorf("Expected the language name in the prompt:\n%s", prompt)
+	}
+}
+
+func TestExplain_UnsupportedLanguage(t *testing.T) {
+	fake := newFakeOpenAI("")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	for _, lang := range []string{"eo", "xx-YY", "english"} {
+		if _, err := service.Explain(context.Background(), 25, lang, ""); !errors.Is(err, ErrUnsupportedLanguage) {
+			t.Errorf("%s: expected ErrUnsupportedLanguage, got %v", lang, err)
+		}
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected no completion, got %d", len(fake.requests))
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		tag  string
+		code string
+		ok   bool
+	}{
+		{"en", "en", true},
+		{"EN-us", "en", true},
+		{"pt-BR", "pt-BR", true},
+		{"pt_br", "pt-BR", true},
+		{"pt", "pt-BR", true},
+		{"es-MX", "es", true},
+		{"ja-hrkt", "ja-Hrkt", true},
+		{"zh-TW", "zh-Hant", true},
+		{"zh", "zh-Hans", true},
+		{" roomaji ", "roomaji", true},
+		{"eo", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		code, ok := ParseLanguage(tt.tag)
+		if code != tt.code || ok != tt.ok {
+			t.Errorf("ParseLanguage(%q) = %q, %v, expected %q, %v", tt.tag, code, ok, tt.code, tt.ok)
+		}
+	}
+}
+
+func TestPromptVersions(t *testing.T) {
+	seen := make(map[string]string)
+	for persona := range personaPrompts {
+		version := promptVersions[persona]
+		if len(version) != promptVersionLength || strings.Trim(version, "0123456789abcdef") != "" {
+			t.Errorf("%s: unexpected version %q", persona, version)
+		}
+		if other, ok := seen[version]; ok {
+			t.Errorf("%s and %s share the version %s", persona, other, version)
+		}
+		seen[version] = persona
+	}
+
+	// Versions only change with the prompt
+	for persona, version := range newPromptVersions() {
+		if promptVersions[persona] != version {
+			t.Errorf("%s: expected a stable version", persona)
+		}
+	}
+
+	// The names of the languages are part of the prompt
+	languageNames["en"] = "American English"
+	defer func() { languageNames["en"] = "English" }()
+	for persona, version := range newPromptVersions() {
+		if promptVersions[persona] == version {
+			t.Errorf("%s: expected a new version when a language name changes", persona)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// AnonymousClient identifies the requests that do not send an API key
+const AnonymousClient = "anonymous"
+
+// clientIDLength is the number of hex digits of the API key hash kept in client IDs
+const clientIDLength = 12
+
+// clientKey is the context key of the client ID
+type clientKey struct{}
+
+// WithClient returns a context carrying the ID of the client with the API key
+func WithClient(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, clientKey{}, ClientID(apiKey))
+}
+
+// ClientFrom returns the ID of the client making the request
+func ClientFrom(ctx context.Context) string {
+	if id, ok := ctx.Value(clientKey{}).(string); ok {
+		return id
+	}
+	return AnonymousClient
+}
+
+// ClientID returns the ID of the client with the API key. The ID is a hash,
+// so usage reports and logs never reveal the key.
+func ClientID(apiKey string) string {
+	if apiKey == "" {
+		return AnonymousClient
+	}
+	hash := sha256.Sum256([]byte(apiKey))
+	return "key-" + hex.EncodeToString(hash[:])[:clientIDLength]
+}
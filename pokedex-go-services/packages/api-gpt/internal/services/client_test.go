@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestClientID(t *testing.T) {
+	if ClientID("") != AnonymousClient {
+		t.Errorf("Expected the anonymous client without a key, got %s", ClientID(""))
+	}
+
+	id := ClientID("secret-key")
+	if !strings.HasPrefix(id, "key-") || len(id) != len("key-")+clientIDLength || strings.Contains(id, "secret") {
+		t.Errorf("Unexpected client ID %s", id)
+	}
+	if ClientID("secret-key") != id || ClientID("other-key") == id {
+		t.Error("Expected one stable ID per key")
+	}
+}
+
+func TestClientFrom(t *testing.T) {
+	if client := ClientFrom(context.Background()); client != AnonymousClient {
+		t.Errorf("Expected the anonymous client, got %s", client)
+	}
+
+	ctx := WithClient(context.Background(), "secret-key")
+	if client := ClientFrom(ctx); client != ClientID("secret-key") {
+		t.Errorf("Expected the client of the key, got %s", client)
+	}
+}
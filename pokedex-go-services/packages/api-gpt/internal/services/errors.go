@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the services. Use errors.Is to check them.
+var (
+	// ErrNotFound means the requested Pokémon does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidRequest means the Pokémon API rejected the request parameters
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrUpstreamUnavailable means an upstream service could not be reached or failed
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrRateLimited means an upstream service rejected the request due to rate limiting
+	ErrRateLimited = errors.New("upstream rate limit exceeded")
+	// ErrDecode means an upstream service returned a payload that could not be decoded
+	ErrDecode = errors.New("invalid upstream payload")
+	// ErrNotConfigured means the AI provider has no credentials
+	ErrNotConfigured = errors.New("AI provider not configured")
+	// ErrBudgetExhausted means a budget of AI spending has been reached
+	ErrBudgetExhausted = errors.New("AI budget exhausted")
+	// ErrUnpricedModel means budgets are set for a model without a price
+	ErrUnpricedModel = errors.New("AI model has no price")
+	// ErrUnknownPersona means the request asks for an audience persona that does not exist
+	ErrUnknownPersona = errors.New("unknown persona")
+	// ErrUnsupportedLanguage means the request asks for a language explanations are not written in
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+)
+
+// UpstreamError represents a failed call to the Pokémon API or the AI
+// provider. It matches one of the sentinel errors with errors.Is and keeps
+// the underlying cause.
+type UpstreamError struct {
+	Service    string
+	Path       string
+	StatusCode int // Zero when no response was received
+	// Code and Message are the error reported by the Pokémon API, if any
+	Code    string
+	Message string
+	Kind    error
+	Cause   error
+}
+
+// Error returns the error message
+func (e *UpstreamError) Error() string {
+	msg := fmt.Sprintf("%s %s: %v", e.Service, e.Path, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error and the underlying cause
+func (e *UpstreamError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// kindForStatus returns the sentinel error of a failed response status
+func kindForStatus(status int) error {
+	switch {
+	case status == 404:
+		return ErrNotFound
+	case status == 429:
+		return ErrRateLimited
+	case status >= 400 && status < 500:
+		return ErrInvalidRequest
+	default:
+		return ErrUpstreamUnavailable
+	}
+}
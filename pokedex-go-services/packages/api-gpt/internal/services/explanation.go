@@ -0,0 +1,446 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"pokedexia-gpt/internal/store"
+	"pokedexia-gpt/internal/types"
+)
+
+// DefaultLanguage is the language of explanations when the request does not set one
+const DefaultLanguage = "en"
+
+// promptVersionLength is the number of hex digits of the prompt hash kept as its version
+const promptVersionLength = 12
+
+// maxFlavorTexts limits the Pokédex entries included in the prompt
+const maxFlavorTexts = 3
+
+// languageNames names the languages of the Pokémon API in the prompt
+var languageNames = map[string]string{
+	"en":      "English",
+	"pt-BR":   "Brazilian Portuguese",
+	"es":      "Spanish",
+	"fr":      "French",
+	"de":      "German",
+	"it":      "Italian",
+	"cs":      "Czech",
+	"ja":      "Japanese",
+	"ja-Hrkt": "Japanese (kana)",
+	"roomaji": "Japanese written in Latin letters",
+	"ko":      "Korean",
+	"zh-Hans": "Simplified Chinese",
+	"zh-Hant": "Traditional Chinese",
+}
+
+// languageAliases maps other lowercase language tags to the code they are
+// explained in
+var languageAliases = map[string]string{
+	"pt":    "pt-BR",
+	"pt-pt": "pt-BR",
+	"ja-jp": "ja",
+	"zh":    "zh-Hans",
+	"zh-cn": "zh-Hans",
+	"zh-sg": "zh-Hans",
+	"zh-tw": "zh-Hant",
+	"zh-hk": "zh-Hant",
+}
+
+// languageCodes maps every accepted lowercase language tag to its code
+var languageCodes = newLanguageCodes()
+
+// Audience personas of the explanations
+const (
+	PersonaGeneral     = "general"
+	PersonaKids        = "kids"
+	PersonaCompetitive = "competitive"
+)
+
+// DefaultPersona is the audience of explanations when the request does not set one
+const DefaultPersona = PersonaGeneral
+
+// systemPrompt sets the role of the model in every explanation
+const systemPrompt = "You are a Pokédex expert who explains Pokémon. " +
+	"Be friendly and accurate, and only state facts that are given to you or that are well " +
+	"known from the games. Do not use Markdown."
+
+// personaPrompts describes the audience of each persona to the model
+var personaPrompts = map[string]string{
+	PersonaGeneral:     "Write for fans of every age.",
+	PersonaKids:        "Write for young children, with short sentences, simple words and a playful tone.",
+	PersonaCompetitive: "Write for competitive players, focusing on its role in battle, its stat spread, its abilities and its type matchups.",
+}
+
+// instructionsFormat asks for the explanation, given the name of the language
+const instructionsFormat = "Write a short explanation of this Pokémon in %s, in two or three paragraphs: " +
+	"what it is, what its types mean in battle, its strongest and weakest stats, and how its abilities help it."
+
+// promptVersions holds the prompt version of each persona. Stored
+// explanations are only reused for the same version.
+var promptVersions = newPromptVersions()
+
+// ExplanationService generates explanations of Pokémon with an LLM provider.
+// With a store, generated explanations are persisted and reused.
+type ExplanationService struct {
+	pokemon  PokemonSource
+	provider LLMProvider
+	fallback LLMProvider
+	store    store.Store
+	usage    *UsageTracker
+	flights  flightGroup
+	now      func() time.Time
+}
+
+// generateFunc generates the completion of a prompt with a provider
+type generateFunc func(ctx context.Context, provider LLMProvider, prompt Prompt) (*Completion, error)
+
+// NewExplanationService creates a new instance of the service
+func NewExplanationService(pokemon PokemonSource, provider LLMProvider) *ExplanationService {
+	return &ExplanationService{
+		pokemon:  pokemon,
+		provider: provider,
+		fallback: NewTemplateProvider(),
+		now:      time.Now,
+	}
+}
+
+// WithStore sets the store where generated explanations are persisted
+func (s *ExplanationService) WithStore(st store.Store) *ExplanationService {
+	s.store = st
+	return s
+}
+
+// WithUsage sets the tracker that records the usage of each generation and
+// enforces the budgets. Past a budget, explanations that are not stored are
+// written by the template provider.
+func (s *ExplanationService) WithUsage(usage *UsageTracker) *ExplanationService {
+	s.usage = usage
+	return s
+}
+
+// Explain returns the explanation of a Pokémon in the language for the
+// persona, from the store when it was already generated
+func (s *ExplanationService) Explain(ctx context.Context, id int, lang, persona string) (*types.AIExplanation, error) {
+	return s.explain(ctx, id, lang, persona, true, generate)
+}
+
+// ExplainStream returns the explanation of a Pokémon like Explain, passing
+// each piece of text to onToken as the provider writes it. A stored
+// explanation, or one generated for another request at the same time, is
+// passed on as a single piece.
+func (s *ExplanationService) ExplainStream(ctx context.Context, id int, lang, persona string, onToken TokenFunc) (*types.AIExplanation, error) {
+	// The generation may be shared with other requests and outlive this call,
+	// so tokens are only passed on until it returns. When this client is gone,
+	// the generation stops, or goes on silently while others wait for it.
+	var mu sync.Mutex
+	var streamed, returned bool
+	var streamErr error
+	explanation, err := s.explain(ctx, id, lang, persona, true, func(ctx context.Context, provider LLMProvider, prompt Prompt) (*Completion, error) {
+		mu.Lock()
+		streamed = true
+		mu.Unlock()
+
+		return provider.Stream(ctx, prompt, func(token string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !returned && streamErr == nil {
+				streamErr = onToken(token)
+			}
+			if streamErr != nil && !s.flights.shared(ctx) {
+				return streamErr
+			}
+			return ctx.Err()
+		})
+	})
+
+	mu.Lock()
+	returned = true
+	generated, tokenErr := streamed, streamErr
+	mu.Unlock()
+
+	// The error of onToken comes first, since the generation may have gone on
+	if tokenErr != nil {
+		return nil, tokenErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !generated {
+		if err := onToken(explanation.Explanation); err != nil {
+			return nil, err
+		}
+	}
+	return explanation, nil
+}
+
+// Regenerate generates the explanation of a Pokémon again, replacing the
+// stored one. It fails with a *BudgetError when a budget is exhausted.
+func (s *ExplanationService) Regenerate(ctx context.Context, id int, lang, persona string) (*types.AIExplanation, error) {
+	return s.explain(ctx, id, lang, persona, false, generate)
+}
+
+// generate generates the completion of a prompt in one piece
+func generate(ctx context.Context, provider LLMProvider, prompt Prompt) (*Completion, error) {
+	return provider.Generate(ctx, prompt)
+}
+
+// explain returns the stored explanation when reuse is allowed, or fetches
+// the Pokémon data, generates the explanation with the function and stores it
+func (s *ExplanationService) explain(ctx context.Context, id int, lang, persona string, reuse bool, run generateFunc) (*types.AIExplanation, error) {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	code, ok := ParseLanguage(lang)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedLanguage, lang)
+	}
+	lang = code
+
+	if persona == "" {
+		persona = DefaultPersona
+	}
+
+	version, ok := promptVersions[persona]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPersona, persona)
+	}
+
+	key := CacheKey{
+		PokemonID:     id,
+		Language:      lang,
+		Persona:       persona,
+		PromptVersion: version,
+		Model:         s.provider.Model(),
+	}
+	if reuse {
+		if explanation := s.readStore(key); explanation != nil {
+			return explanation, nil
+		}
+	}
+
+	// Past a budget, fall back to the template provider, which costs nothing.
+	// Each caller is checked with its own client before sharing a generation.
+	provider := s.provider
+	degraded := false
+	if s.usage != nil {
+		if err := s.usage.Allow(ClientFrom(ctx)); err != nil {
+			if !reuse {
+				return nil, err
+			}
+			log.Printf("%v, explaining Pokémon %d with the %s provider", err, id, s.fallback.Name())
+			provider, degraded = s.fallback, true
+			key.Model = provider.Model()
+		}
+	}
+
+	if !reuse {
+		return s.generate(ctx, key, provider, degraded, run)
+	}
+
+	// Concurrent misses for the same explanation share a single generation,
+	// billed to the first caller. Degraded callers never share one with the
+	// others. Only the first caller runs the function, so only its tokens are
+	// streamed.
+	explanation, _, err := s.flights.do(ctx, flightKey(key, degraded), func(ctx context.Context) (*types.AIExplanation, error) {
+		return s.generate(ctx, key, provider, degraded, run)
+	})
+	return explanation, err
+}
+
+// flightKey returns the key of the shared generation of an explanation, which
+// tells degraded generations apart even when the provider is the fallback
+func flightKey(key CacheKey, degraded bool) string {
+	return fmt.Sprintf("%s/degraded=%t", key, degraded)
+}
+
+// generate fetches the Pokémon data, generates the explanation of the key with
+// the provider and the function, bills it to the client and stores it
+func (s *ExplanationService) generate(ctx context.Context, key CacheKey, provider LLMProvider, degraded bool, run generateFunc) (*types.AIExplanation, error) {
+	id, lang, persona := key.PokemonID, key.Language, key.Persona
+	client := ClientFrom(ctx)
+
+	pokemon, err := s.pokemon.GetPokemon(ctx, id, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// The species only enriches the prompt; the Pokémon API resolves alternate
+	// forms to their base species, and a missing species is left out
+	species, err := s.pokemon.GetSpecies(ctx, id, lang)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		if !errors.Is(err, ErrNotFound) {
+			log.Printf("Error fetching the species of Pokémon %d, explaining without it: %v", id, err)
+		}
+		species = nil
+	}
+
+	completion, err := run(ctx, provider, Prompt{
+		Messages: BuildPrompt(pokemon, species, lang, persona),
+		Pokemon:  pokemon,
+		Species:  species,
+		Language: lang,
+		Persona:  persona,
+	})
+
+	// Failed generations are billed too, as far as the provider got
+	if completion != nil && s.usage != nil {
+		s.usage.Record(client, completion.Model, completion.Usage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &types.AIExplanation{
+		PokemonID:     pokemon.ID,
+		Name:          displayName(pokemon),
+		Language:      lang,
+		Persona:       persona,
+		Explanation:   completion.Text,
+		Model:         completion.Model,
+		PromptVersion: key.PromptVersion,
+		GeneratedAt:   s.now().UTC().Format(time.RFC3339),
+		Degraded:      degraded,
+	}
+	s.persist(key, explanation)
+
+	return explanation, nil
+}
+
+// BuildPrompt returns the chat messages asking for the explanation of a
+// Pokémon for the persona. The species is optional.
+func BuildPrompt(pokemon *types.PokemonResponse, species *types.SpeciesResponse, lang, persona string) []types.ChatMessage {
+	var facts strings.Builder
+
+	fmt.Fprintf(&facts, "Name: %s\n", displayName(pokemon))
+	fmt.Fprintf(&facts, "National Pokédex number: %d\n", pokemon.ID)
+	fmt.Fprintf(&facts, "Types: %s\n", strings.Join(pokemon.Types, ", "))
+	fmt.Fprintf(&facts, "Abilities: %s\n", strings.Join(pokemon.Abilities, ", "))
+	fmt.Fprintf(&facts, "Height: %.1f m, weight: %.1f kg\n", float64(pokemon.Height)/10, float64(pokemon.Weight)/10)
+
+	stats := pokemon.Stats
+	fmt.Fprintf(&facts, "Base stats: HP %d, Attack %d, Defense %d, Sp. Atk %d, Sp. Def %d, Speed %d\n",
+		stats.HP, stats.Attack, stats.Defense, stats.SpecialAttack, stats.SpecialDefense, stats.Speed)
+
+	if species != nil {
+		if species.Generation != "" {
+			fmt.Fprintf(&facts, "Introduced in: %s\n", species.Generation)
+		}
+		if species.IsLegendary {
+			facts.WriteString("It is a Legendary Pokémon\n")
+		}
+		if species.IsMythical {
+			facts.WriteString("It is a Mythical Pokémon\n")
+		}
+		if species.EvolvesFrom != "" {
+			fmt.Fprintf(&facts, "Evolves from: %s\n", species.EvolvesFrom)
+		}
+		if species.Localized != nil {
+			if species.Localized.Genus != "" {
+				fmt.Fprintf(&facts, "Category: %s\n", species.Localized.Genus)
+			}
+			for i, entry := range species.Localized.FlavorText {
+				if i == maxFlavorTexts {
+					break
+				}
+				fmt.Fprintf(&facts, "Pokédex entry (%s): %s\n", entry.Version, entry.Text)
+			}
+		}
+	}
+
+	return []types.ChatMessage{
+		{Role: "system", Content: systemPrompt + " " + personaPrompts[persona]},
+		{Role: "user", Content: facts.String() + "\n" + fmt.Sprintf(instructionsFormat, languageName(lang))},
+	}
+}
+
+// newPromptVersions hashes the prompt of each persona built for a sample
+// Pokémon, so any change to the prompt template gives a new version
+func newPromptVersions() map[string]string {
+	// The sample sets every field, so changes to any of them are noticed
+	pokemon := &types.PokemonResponse{
+		ID: 1, Name: "sample", LocalizedName: "Sample", Types: []string{"type"}, Abilities: []string{"ability"},
+		Height: 1, Weight: 1, Stats: types.Stats{HP: 1, Attack: 1, Defense: 1, SpecialAttack: 1, SpecialDefense: 1, Speed: 1},
+	}
+	species := &types.SpeciesResponse{
+		Generation: "generation", IsLegendary: true, IsMythical: true, EvolvesFrom: "sample",
+		Localized: &types.LocalizedSpecies{Genus: "genus", FlavorText: []types.FlavorText{{Version: "version", Text: "text"}}},
+	}
+
+	// The prompt only holds the name of one language, so the names of all
+	// of them are hashed too
+	codes := make([]string, 0, len(languageNames))
+	for code := range languageNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	versions := make(map[string]string, len(personaPrompts))
+	for persona := range personaPrompts {
+		hash := sha256.New()
+		for _, message := range BuildPrompt(pokemon, species, "language", persona) {
+			fmt.Fprintf(hash, "%s\x00%s\x00", message.Role, message.Content)
+		}
+		for _, code := range codes {
+			fmt.Fprintf(hash, "%s\x00%s\x00", code, languageNames[code])
+		}
+		versions[persona] = hex.EncodeToString(hash.Sum(nil))[:promptVersionLength]
+	}
+	return versions
+}
+
+// displayName returns the localized name of the Pokémon when there is one
+func displayName(pokemon *types.PokemonResponse) string {
+	if pokemon.LocalizedName != "" {
+		return pokemon.LocalizedName
+	}
+	return pokemon.Name
+}
+
+// ParseLanguage returns the code of a supported language tag such as "pt-br"
+// or "PT", or false when the language is not supported
+func ParseLanguage(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if code, ok := languageCodes[tag]; ok {
+		return code, true
+	}
+
+	// Fall back to the primary subtag, e.g. "es-MX" is explained in "es"
+	if base, _, found := strings.Cut(tag, "-"); found {
+		code, ok := languageCodes[base]
+		return code, ok
+	}
+	return "", false
+}
+
+// newLanguageCodes maps the lowercase form of each language code and alias
+// to the code
+func newLanguageCodes() map[string]string {
+	codes := make(map[string]string, len(languageNames)+len(languageAliases))
+	for code := range languageNames {
+		codes[strings.ToLower(code)] = code
+	}
+	for alias, code := range languageAliases {
+		codes[alias] = code
+	}
+	return codes
+}
+
+// languageName returns the English name of a language code for the prompt
+func languageName(lang string) string {
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+func newTestExplanationService(fake *fakeOpenAI, api string) *ExplanationService {
+	cfg := newOpenAIConfig(fake.URL)
+	cfg.APIBaseURL = api
+
+	service := NewExplanationService(NewPokemonClient(cfg), NewOpenAIProvider(cfg))
+	service.now = func() time.Time {
+		return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return service
+}
+
+func TestExplain(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	explanation, err := service.Explain(context.Background(), 25, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := types.AIExplanation{
+		PokemonID:     25,
+		Name:          "Pikachu",
+		Language:      DefaultLanguage,
+		Persona:       DefaultPersona,
+		Explanation:   "Pikachu stores electricity in its cheeks.",
+		Model:         "gpt-4o-mini-2024-07-18",
+		PromptVersion: promptVersions[DefaultPersona],
+		GeneratedAt:   "2024-05-01T12:00:00Z",
+	}
+	if *explanation != expected {
+		t.Errorf("Expected %+v, got %+v", expected, *explanation)
+	}
+
+	// The prompt carries the Pokémon and species facts
+	prompt := fake.requests[0].Messages[1].Content
+	for _, fact := range []string{"Types: electric", "Speed 90", "Evolves from: pichu", "Category: Mouse Pokémon", "lightning storms", "in English"} {
+		if !strings.Contains(prompt, fact) {
+			t.Errorf("Expected the prompt to contain %q:\n%s", fact, prompt)
+		}
+	}
+}
+
+func TestExplain_WithoutSpecies(t *testing.T) {
+	fake := newFakeOpenAI("Alolan Raichu surfs on its tail.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	explanation, err := service.Explain(context.Background(), 10100, "es", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explanation.Name != "raichu-alola" || explanation.Language != "es" {
+		t.Errorf("Unexpected explanation %+v", explanation)
+	}
+
+	prompt := fake.requests[0].Messages[1].Content
+	if !strings.Contains(prompt, "in Spanish") || strings.Contains(prompt, "Category") {
+		t.Errorf("Unexpected prompt:\n%s", prompt)
+	}
+}
+
+func TestExplain_Errors(t *testing.T) {
+	fake := newFakeOpenAI("")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	if _, err := service.Explain(context.Background(), 150, "", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("Expected no completion for a missing Pokémon, got %d", len(fake.requests))
+	}
+
+	fake.fail(500)
+	if _, err := service.Explain(context.Background(), 25, "", ""); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	pokemon := &types.PokemonResponse{ID: 150, Name: "mewtwo", Types: []string{"psychic"}, Height: 20, Weight: 1220}
+	species := &types.SpeciesResponse{IsLegendary: true}
+
+	messages := BuildPrompt(pokemon, species, "pt-BR", PersonaGeneral)
+
+	if len(messages) != 2 || messages[0].Role != "system" || messages[1].Role != "user" {
+		t.Fatalf("Unexpected messages %+v", messages)
+	}
+	for _, fact := range []string{"Name: mewtwo", "Height: 2.0 m, weight: 122.0 kg", "Legendary", "in Brazilian Portuguese"} {
+		if !strings.Contains(messages[1].Content, fact) {
+			t.Errorf("Expected the prompt to contain %q:\n%s", fact, messages[1].Content)
+		}
+	}
+
+	// The persona sets the audience
+	if !strings.Contains(BuildPrompt(pokemon, species, "en", PersonaKids)[0].Content, "young children") {
+		t.Error("Expected the persona in the system prompt")
+	}
+
+	// Unknown languages are named by their code
+	if !strings.Contains(BuildPrompt(pokemon, nil, "eo", PersonaGeneral)[1].Content, "in eo") {
+		t.Error("Expected the language code in the prompt")
+	}
+}
+
+func TestExplain_TemplateProvider(t *testing.T) {
+	api := newFakeAPI()
+	defer api.Close()
+
+	cfg := &config.Config{APIBaseURL: api.URL}
+	service := NewExplanationService(NewPokemonClient(cfg), NewTemplateProvider())
+
+	explanation, err := service.Explain(context.Background(), 25, "", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explanation.Model != templateModel || !strings.HasPrefix(explanation.Explanation, "Pikachu is Pokémon #25") {
+		t.Errorf("Unexpected explanation %+v", explanation)
+	}
+}
+
+func TestExplainStream(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	service := newTestExplanationService(fake, api.URL)
+
+	var tokens []string
+	explanation, err := service.ExplainStream(context.Background(), 25, "en", "", func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tokens) != 6 || strings.Join(tokens, "") != explanation.Explanation {
+		t.Errorf("Unexpected tokens %q for %q", tokens, explanation.Explanation)
+	}
+	if explanation.Name != "Pikachu" || explanation.GeneratedAt != "2024-05-01T12:00:00Z" {
+		t.Errorf("Unexpected explanation %+v", explanation)
+	}
+
+	// Nothing is streamed for a missing Pokémon
+	if _, err := service.ExplainStream(context.Background(), 150, "", "", func(string) error {
+		t.Error("Expected no tokens")
+		return nil
+	}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"pokedexia-gpt/internal/types"
+)
+
+// flightGroup deduplicates concurrent generations of the same explanation, so
+// only one of them calls the provider while the others wait for its result
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCallKey is the context key of the call a function runs for
+type flightCallKey struct{}
+
+// flightCall represents a generation that is in flight
+type flightCall struct {
+	done        chan struct{}
+	explanation *types.AIExplanation
+	err         error
+	waiters     int
+	cancel      context.CancelFunc
+}
+
+// do runs fn once per key at a time. Callers that arrive while a call for the
+// same key is in flight wait for it and receive a copy of the same result or
+// error, with shared set.
+//
+// The shared call is detached from the cancellation of any single caller: a
+// caller whose context is done stops waiting and gets the context error, and
+// the call itself is cancelled only once every caller has stopped waiting.
+// The last caller waits for the cancelled call to return.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*types.AIExplanation, error)) (explanation *types.AIExplanation, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, shared := g.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		callCtx = context.WithValue(callCtx, flightCallKey{}, call)
+		g.calls[key] = call
+
+		go func() {
+			explanation, err := fn(callCtx)
+
+			g.mu.Lock()
+			call.explanation, call.err = explanation, err
+			g.forget(key, call)
+			g.mu.Unlock()
+
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, shared, call.err
+		}
+		copied := *call.explanation
+		return &copied, shared, nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		last := call.waiters == 0
+		if last {
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+
+		if last {
+			<-call.done
+		}
+		return nil, shared, ctx.Err()
+	}
+}
+
+// forget removes the call from the group so later callers start a new one.
+// The caller must hold the lock.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// shared reports whether other callers wait for the call running with the
+// context, besides the one that started it
+func (g *flightGroup) shared(ctx context.Context) bool {
+	call, ok := ctx.Value(flightCallKey{}).(*flightCall)
+	if !ok {
+		return false
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return call.waiters > 1
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// blockingProvider is a template provider that counts its generations and
+// holds them until it is released
+type blockingProvider struct {
+	TemplateProvider
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *blockingProvider) Generate(ctx context.Context, prompt Prompt) (*Completion, error) {
+	p.calls.Add(1)
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return p.TemplateProvider.Generate(ctx, prompt)
+}
+
+// waitForWaiters waits until the expected number of callers share the call for the key
+func waitForWaiters(t *testing.T, g *flightGroup, key string, expected int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		g.mu.Lock()
+		waiters := 0
+		if call, ok := g.calls[key]; ok {
+			waiters = call.waiters
+		}
+		g.mu.Unlock()
+
+		if waiters == expected {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d callers waiting for %s, got %d", expected, key, waiters)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestExplain_SharesConcurrentGenerations(t *testing.T) {
+	api := newFakeAPI()
+	defer api.Close()
+
+	provider := &blockingProvider{release: make(chan struct{})}
+	service := NewExplanationService(NewPokemonClient(&config.Config{APIBaseURL: api.URL}), provider)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			explanation, err := service.Explain(context.Background(), 25, "pt-br", "")
+			if err == nil {
+				results[i] = explanation.Explanation
+			}
+			errs[i] = err
+		}(i)
+	}
+
+	key := CacheKey{PokemonID: 25, Language: "pt-BR", Persona: DefaultPersona, PromptVersion: promptVersions[DefaultPersona], Model: templateModel}
+	waitForWaiters(t, &service.flights, flightKey(key, false), callers)
+	close(provider.release)
+	wg.Wait()
+
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 generation, got %d", calls)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || results[i] == "" || results[i] != results[0] {
+			t.Errorf("Caller %d: expected the shared explanation, got %q, %v", i, results[i], errs[i])
+		}
+	}
+}
+
+func TestFlightGroup_CancelsWhenEveryCallerLeaves(t *testing.T) {
+	var g flightGroup
+	started := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	other, cancelOther := context.WithCancel(context.Background())
+
+	var callCtx context.Context
+	fn := func(ctx context.Context) (*types.AIExplanation, error) {
+		callCtx = ctx
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(ctx, "key", fn)
+		done <- err
+	}()
+	<-started
+	go func() {
+		g.do(other, "key", fn)
+	}()
+	waitForWaiters(t, &g, "key", 2)
+
+	// The call goes on while another caller waits
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if callCtx.Err() != nil {
+		t.Error("Expected the call to go on for the other caller")
+	}
+
+	cancelOther()
+	select {
+	case <-callCtx.Done():
+	case <-time.After(time.Second):
+		t.Error("Expected the call to be cancelled once every caller left")
+	}
+}
+
+func TestExplain_ChecksBudgetsPerClient(t *testing.T) {
+	api := newFakeAPI()
+	defer api.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := newTestUsageTracker(&config.Config{AIKeyDailyBudget: testCost / 2}, &now)
+	usage.Record(ClientID("bob"), "gpt-4o-mini", testUsage)
+
+	provider := &blockingProvider{release: make(chan struct{})}
+	service := NewExplanationService(NewPokemonClient(&config.Config{APIBaseURL: api.URL}), provider).WithUsage(usage)
+
+	type result struct {
+		explanation *types.AIExplanation
+		err         error
+	}
+	explain := func(apiKey string) chan result {
+		done := make(chan result, 1)
+		go func() {
+			explanation, err := service.Explain(WithClient(context.Background(), apiKey), 25, "en", "")
+			done <- result{explanation, err}
+		}()
+		return done
+	}
+
+	key := CacheKey{PokemonID: 25, Language: "en", Persona: DefaultPersona, PromptVersion: promptVersions[DefaultPersona], Model: templateModel}
+
+	alice := explain("alice")
+	waitForWaiters(t, &service.flights, flightKey(key, false), 1)
+	carol := explain("carol")
+	waitForWaiters(t, &service.flights, flightKey(key, false), 2)
+
+	// Bob is over budget, so he gets his own degraded explanation instead
+	// of sharing the generation of the others
+	select {
+	case bob := <-explain("bob"):
+		if bob.err != nil || !bob.explanation.Degraded {
+			t.Errorf("Expected a degraded explanation for bob, got %+v, %v", bob.explanation, bob.err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected bob not to wait for the generation of the others")
+	}
+
+	close(provider.release)
+	for name, done := range map[string]chan result{"alice": alice, "carol": carol} {
+		r := <-done
+		if r.err != nil || r.explanation.Degraded {
+			t.Errorf("Expected the shared explanation for %s, got %+v, %v", name, r.explanation, r.err)
+		}
+	}
+	if calls := provider.calls.Load(); calls != 1 {
+		t.Errorf("Expected 1 generation with the provider, got %d", calls)
+	}
+}
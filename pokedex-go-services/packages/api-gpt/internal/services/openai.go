@@ -0,0 +1,318 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// chatCompletionsPath is the chat completions endpoint, relative to the base URL
+const chatCompletionsPath = "/chat/completions"
+
+// streamDone is the data of the event that ends a streamed completion
+const streamDone = "[DONE]"
+
+// maxStreamLineSize limits the size of each line of a streamed completion
+const maxStreamLineSize = 1 << 20
+
+// charsPerToken approximates the length of a token to estimate the usage
+// of completions the provider did not report it for
+const charsPerToken = 4
+
+// OpenAIProvider generates text with the chat completions API of OpenAI or
+// of a compatible server, such as llama.cpp, Ollama or vLLM
+type OpenAIProvider struct {
+	name        string
+	apiKey      string
+	requireKey  bool
+	baseURL     string
+	model       string
+	maxTokens   int
+	temperature float64
+	timeout     time.Duration
+	httpClient  *http.Client
+}
+
+// Ensure the provider satisfies the interface
+var _ LLMProvider = (*OpenAIProvider)(nil)
+
+// NewOpenAIProvider creates a provider for the OpenAI API, which requires an API key
+func NewOpenAIProvider(cfg *config.Config) *OpenAIProvider {
+	return &OpenAIProvider{
+		name:        ProviderOpenAI,
+		apiKey:      cfg.OpenAIAPIKey,
+		requireKey:  true,
+		baseURL:     strings.TrimSuffix(cfg.OpenAIBaseURL, "/"),
+		model:       cfg.OpenAIModel,
+		maxTokens:   cfg.LLMMaxTokens,
+		temperature: cfg.LLMTemperature,
+		timeout:     cfg.LLMTimeout,
+		httpClient:  &http.Client{},
+	}
+}
+
+// NewCompatibleProvider creates a provider for an OpenAI-compatible server.
+// Local servers usually need no API key, so it is only sent when set.
+func NewCompatibleProvider(cfg *config.Config) *OpenAIProvider {
+	return &OpenAIProvider{
+		name:        ProviderCompatible,
+		apiKey:      cfg.CompatibleAPIKey,
+		baseURL:     strings.TrimSuffix(cfg.CompatibleBaseURL, "/"),
+		model:       cfg.CompatibleModel,
+		maxTokens:   cfg.LLMMaxTokens,
+		temperature: cfg.LLMTemperature,
+		timeout:     cfg.LLMTimeout,
+		httpClient:  &http.Client{},
+	}
+}
+
+// Name returns the provider name
+func (p *OpenAIProvider) Name() string {
+	return p.name
+}
+
+// Model returns the model used for completions
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+// Configured reports whether the provider has the API key it needs
+func (p *OpenAIProvider) Configured() bool {
+	return p.apiKey != "" || !p.requireKey
+}
+
+// Generate generates the reply to the chat messages of the prompt
+func (p *OpenAIProvider) Generate(ctx context.Context, prompt Prompt) (*Completion, error) {
+	if !p.Configured() {
+		return nil, ErrNotConfigured
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	resp, err := p.send(ctx, p.newRequest(prompt))
+	if err != nil {
+		return p.abandoned(ctx, prompt), err
+	}
+	defer resp.Body.Close()
+
+	// The completion is billed from here on, even when it cannot be read
+	partial := &Completion{Model: p.model}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return estimateUsage(partial, prompt), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrUpstreamUnavailable, Cause: err}
+	}
+
+	var completion types.ChatCompletionResponse
+	if err := json.Unmarshal(body, &completion); err != nil {
+		return estimateUsage(partial, prompt), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrDecode, Cause: err}
+	}
+
+	// Some compatible servers do not report the model
+	if completion.Model != "" {
+		partial.Model = completion.Model
+	}
+	partial.Usage = completion.Usage
+
+	if len(completion.Choices) == 0 {
+		return estimateUsage(partial, prompt), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrDecode, Message: "no choices in the completion"}
+	}
+
+	partial.Text = strings.TrimSpace(completion.Choices[0].Message.Content)
+	return estimateUsage(partial, prompt), nil
+}
+
+// Stream generates the reply to the chat messages of the prompt, reading the
+// completion as Server-Sent Events and passing on the text of each chunk
+func (p *OpenAIProvider) Stream(ctx context.Context, prompt Prompt, onToken TokenFunc) (*Completion, error) {
+	if !p.Configured() {
+		return nil, ErrNotConfigured
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	request := p.newRequest(prompt)
+	request.Stream = true
+	request.StreamOptions = &types.StreamOptions{IncludeUsage: true}
+
+	resp, err := p.send(ctx, request)
+	if err != nil {
+		return p.abandoned(ctx, prompt), err
+	}
+	defer resp.Body.Close()
+
+	// The usage comes in the last chunk, so streams that stop early are
+	// billed with an estimate of the text received so far
+	completion := &Completion{Model: p.model}
+	var text strings.Builder
+	partial := func() *Completion {
+		completion.Text = strings.TrimSpace(text.String())
+		return estimateUsage(completion, prompt)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	for scanner.Scan() {
+		// Blank lines, comments and other fields carry no completion data
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == streamDone {
+			break
+		}
+
+		var chunk types.ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return partial(), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrDecode, Cause: err}
+		}
+		if chunk.Model != "" {
+			completion.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			completion.Usage = *chunk.Usage
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		// Drop the whitespace the model may start with, as Generate trims it
+		token := chunk.Choices[0].Delta.Content
+		if text.Len() == 0 {
+			token = strings.TrimLeftFunc(token, unicode.IsSpace)
+		}
+		if token == "" {
+			continue
+		}
+
+		text.WriteString(token)
+		if err := onToken(token); err != nil {
+			return partial(), err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if ctx.Err() != nil {
+			return partial(), ctx.Err()
+		}
+		return partial(), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrUpstreamUnavailable, Cause: err}
+	}
+	if text.Len() == 0 {
+		return partial(), &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: resp.StatusCode, Kind: ErrDecode, Message: "no text in the completion"}
+	}
+
+	return partial(), nil
+}
+
+// abandoned returns the completion of a request the client gave up on while
+// the provider was working on it, billed for its prompt, or nil when the
+// request failed before that
+func (p *OpenAIProvider) abandoned(ctx context.Context, prompt Prompt) *Completion {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return estimateUsage(&Completion{Model: p.model}, prompt)
+}
+
+// estimateUsage estimates the usage of the completion from the length of the
+// prompt and of the text when the provider did not report it, as compatible
+// servers may not and streams that stop early never do
+func estimateUsage(completion *Completion, prompt Prompt) *Completion {
+	if completion.Usage != (types.Usage{}) {
+		return completion
+	}
+
+	chars := 0
+	for _, message := range prompt.Messages {
+		chars += utf8.RuneCountInString(message.Content)
+	}
+
+	completion.Usage.PromptTokens = estimateTokens(chars)
+	completion.Usage.CompletionTokens = estimateTokens(utf8.RuneCountInString(completion.Text))
+	completion.Usage.TotalTokens = completion.Usage.PromptTokens + completion.Usage.CompletionTokens
+	return completion
+}
+
+// estimateTokens returns the approximate number of tokens of a text
+func estimateTokens(chars int) int {
+	return (chars + charsPerToken - 1) / charsPerToken
+}
+
+// newRequest returns the chat completion request for the prompt
+func (p *OpenAIProvider) newRequest(prompt Prompt) types.ChatCompletionRequest {
+	return types.ChatCompletionRequest{
+		Model:       p.model,
+		Messages:    prompt.Messages,
+		MaxTokens:   p.maxTokens,
+		Temperature: p.temperature,
+	}
+}
+
+// send posts the chat completion request and returns the response when the
+// provider accepts it. The caller closes the response body.
+func (p *OpenAIProvider) send(ctx context.Context, request types.ChatCompletionRequest) (*http.Response, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+chatCompletionsPath, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, &UpstreamError{Service: p.name, Path: chatCompletionsPath, Kind: ErrUpstreamUnavailable, Cause: err}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, p.responseError(resp.StatusCode, body)
+	}
+
+	return resp, nil
+}
+
+// responseError returns the error for a failed chat completion. Requests the
+// provider rejects, such as an invalid key or model, are its failures and
+// not the client's.
+func (p *OpenAIProvider) responseError(status int, body []byte) *UpstreamError {
+	kind := ErrUpstreamUnavailable
+	if status == http.StatusTooManyRequests {
+		kind = ErrRateLimited
+	}
+
+	upstreamErr := &UpstreamError{Service: p.name, Path: chatCompletionsPath, StatusCode: status, Kind: kind}
+
+	var errorResponse types.OpenAIErrorResponse
+	if json.Unmarshal(body, &errorResponse) == nil {
+		upstreamErr.Message = errorResponse.Error.Message
+	}
+	return upstreamErr
+}
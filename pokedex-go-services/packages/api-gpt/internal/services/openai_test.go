@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// fakeOpenAI is a local OpenAI-compatible server that records the requests it receives
+type fakeOpenAI struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []types.ChatCompletionRequest
+	reply    string
+	status   int
+}
+
+func newFakeOpenAI(reply string) *fakeOpenAI {
+	fake := &fakeOpenAI{reply: reply, status: http.StatusOK}
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != chatCompletionsPath {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error"}}`)
+			return
+		}
+
+		var request types.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, request)
+		status := fake.status
+		fake.mu.Unlock()
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"error": {"message": "Rate limit reached", "type": "requests"}}`)
+			return
+		}
+
+		if request.Stream {
+			writeChunks(w, request.Model+"-2024-07-18", " "+fake.reply)
+			return
+		}
+
+		json.NewEncoder(w).Encode(types.ChatCompletionResponse{
+			ID:      "chatcmpl-1",
+			Model:   request.Model + "-2024-07-18",
+			Choices: []types.ChatChoice{{Message: types.ChatMessage{Role: "assistant", Content: "  " + fake.reply + "\n"}, FinishReason: "stop"}},
+			Usage:   types.Usage{PromptTokens: 120, CompletionTokens: 80, TotalTokens: 200},
+		})
+	}))
+	return fake
+}
+
+// writeChunks streams the reply word by word as chat completion chunks,
+// followed by the usage and the end of the stream
+func writeChunks(w http.ResponseWriter, model, reply string) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprint(w, ": keep-alive\n\n")
+
+	for _, word := range strings.SplitAfter(reply, " ") {
+		chunk, _ := json.Marshal(types.ChatCompletionChunk{
+			Model:   model,
+			Choices: []types.ChatChunkChoice{{Delta: types.ChatMessage{Content: word}}},
+		})
+		fmt.Fprintf(w, "data: %s\n\n", chunk)
+	}
+
+	fmt.Fprintf(w, "data: {\"model\": %q, \"choices\": [], \"usage\": {\"prompt_tokens\": 120, \"completion_tokens\": 80, \"total_tokens\": 200}}\n\n", model)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// fail makes the server answer every request with the status
+func (f *fakeOpenAI) fail(status int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status = status
+}
+
+func newOpenAIConfig(url string) *config.Config {
+	return &config.Config{
+		OpenAIAPIKey:   "test-key",
+		OpenAIBaseURL:  url,
+		OpenAIModel:    "gpt-4o-mini",
+		LLMMaxTokens:   300,
+		LLMTemperature: 0.5,
+	}
+}
+
+func TestOpenAIProvider_Generate(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu is an Electric-type Pokémon.")
+	defer fake.Close()
+
+	provider := NewOpenAIProvider(newOpenAIConfig(fake.URL + "/"))
+
+	prompt := Prompt{Messages: []types.ChatMessage{{Role: "user", Content: "Explain Pikachu"}}}
+	completion, err := provider.Generate(context.Background(), prompt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if completion.Text != "Pikachu is an Electric-type Pokémon." {
+		t.Errorf("Unexpected text %q", completion.Text)
+	}
+	if completion.Model != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("Unexpected model %q", completion.Model)
+	}
+	if completion.Usage.TotalTokens != 200 {
+		t.Errorf("Expected 200 tokens, got %d", completion.Usage.TotalTokens)
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(fake.requests))
+	}
+	request := fake.requests[0]
+	if request.Model != "gpt-4o-mini" || request.MaxTokens != 300 || request.Temperature != 0.5 {
+		t.Errorf("Unexpected request settings %+v", request)
+	}
+	if len(request.Messages) != 1 || request.Messages[0].Content != "Explain Pikachu" {
+		t.Errorf("Unexpected messages %+v", request.Messages)
+	}
+}
+
+func TestOpenAIProvider_Errors(t *testing.T) {
+	fake := newFakeOpenAI("")
+	defer fake.Close()
+
+	prompt := Prompt{Messages: []types.ChatMessage{{Role: "user", Content: "Explain Pikachu"}}}
+
+	// Without a key no request is made
+	cfg := newOpenAIConfig(fake.URL)
+	cfg.OpenAIAPIKey = ""
+	if _, err := NewOpenAIProvider(cfg).Generate(context.Background(), prompt); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Expected ErrNotConfigured, got %v", err)
+	}
+
+	// A rejected key is a failure of the provider
+	cfg = newOpenAIConfig(fake.URL)
+	cfg.OpenAIAPIKey = "wrong-key"
+	_, err := NewOpenAIProvider(cfg).Generate(context.Background(), prompt)
+	var upstreamErr *UpstreamError
+	if !errors.Is(err, ErrUpstreamUnavailable) || !errors.As(err, &upstreamErr) {
+		t.Fatalf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if upstreamErr.StatusCode != http.StatusUnauthorized || upstreamErr.Message != "Incorrect API key provided" {
+		t.Errorf("Unexpected error %+v", upstreamErr)
+	}
+
+	fake.fail(http.StatusTooManyRequests)
+	if _, err := NewOpenAIProvider(newOpenAIConfig(fake.URL)).Generate(context.Background(), prompt); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	fake.Close()
+	if _, err := NewOpenAIProvider(newOpenAIConfig(fake.URL)).Generate(context.Background(), prompt); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestOpenAIProvider_NoChoices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "chatcmpl-1", "choices": []}`)
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAIProvider(newOpenAIConfig(server.URL)).Generate(context.Background(), Prompt{})
+	if !errors.Is(err, ErrDecode) {
+		t.Errorf("Expected ErrDecode, got %v", err)
+	}
+}
+
+func TestCompatibleProvider_Generate(t *testing.T) {
+	var authorization string
+	var request types.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&request)
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "Pikachu is an Electric-type Pokémon."}}]}`)
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		CompatibleBaseURL: server.URL + "/v1",
+		CompatibleModel:   "llama3.1",
+	}
+
+	// Local servers usually need no key
+	provider := NewCompatibleProvider(cfg)
+	if !provider.Configured() || provider.Name() != ProviderCompatible {
+		t.Errorf("Expected a configured compatible provider, got %s", provider.Name())
+	}
+
+	completion, err := provider.Generate(context.Background(), Prompt{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if completion.Model != "llama3.1" {
+		t.Errorf("Expected the configured model without one in the response, got %q", completion.Model)
+	}
+	if request.Model != "llama3.1" || authorization != "" {
+		t.Errorf("Unexpected request to model %q with authorization %q", request.Model, authorization)
+	}
+
+	cfg.CompatibleAPIKey = "local-key"
+	if _, err := NewCompatibleProvider(cfg).Generate(context.Background(), Prompt{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authorization != "Bearer local-key" {
+		t.Errorf("Expected the key to be sent, got %q", authorization)
+	}
+}
+
+func TestOpenAIProvider_Stream(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu is an Electric-type Pokémon.")
+	defer fake.Close()
+
+	provider := NewOpenAIProvider(newOpenAIConfig(fake.URL))
+
+	var tokens []string
+	completion, err := provider.Stream(context.Background(), Prompt{}, func(token string) error {
+		tokens = append(tokens, token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tokens) != 5 || tokens[0] != "Pikachu " {
+		t.Errorf("Unexpected tokens %q", tokens)
+	}
+	if strings.Join(tokens, "") != completion.Text || completion.Text != "Pikachu is an Electric-type Pokémon." {
+		t.Errorf("Unexpected text %q", completion.Text)
+	}
+	if completion.Model != "gpt-4o-mini-2024-07-18" || completion.Usage.TotalTokens != 200 {
+		t.Errorf("Unexpected completion %+v", completion)
+	}
+
+	request := fake.requests[0]
+	if !request.Stream || request.StreamOptions == nil || !request.StreamOptions.IncludeUsage {
+		t.Errorf("Expected a streamed request with usage, got %+v", request)
+	}
+}
+
+func TestOpenAIProvider_StreamErrors(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu is an Electric-type Pokémon.")
+	defer fake.Close()
+
+	provider := NewOpenAIProvider(newOpenAIConfig(fake.URL))
+	noop := func(string) error { return nil }
+
+	// The provider stops when the receiver fails
+	stop := errors.New("client gone")
+	calls := 0
+	_, err := provider.Stream(context.Background(), Prompt{}, func(string) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected to stop after 1 token, got %d calls and %v", calls, err)
+	}
+
+	fake.fail(http.StatusTooManyRequests)
+	if _, err := provider.Stream(context.Background(), Prompt{}, noop); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Pikachu\"}}]}\n\ndata: {\"choices\n\n")
+	}))
+	defer server.Close()
+
+	if _, err := NewOpenAIProvider(newOpenAIConfig(server.URL)).Stream(context.Background(), Prompt{}, noop); !errors.Is(err, ErrDecode) {
+		t.Errorf("Expected ErrDecode, got %v", err)
+	}
+}
+
+func TestOpenAIProvider_StreamPartialUsage(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu is an Electric-type Pokémon.")
+	defer fake.Close()
+
+	provider := NewOpenAIProvider(newOpenAIConfig(fake.URL))
+	prompt := Prompt{Messages: []types.ChatMessage{{Role: "user", Content: "Explain Pikachu"}}}
+
+	// A client that leaves before the usage chunk is billed with an estimate
+	completion, err := provider.Stream(context.Background(), prompt, func(string) error {
+		return errors.New("client gone")
+	})
+	if err == nil || completion == nil {
+		t.Fatalf("Expected the partial completion with the error, got %+v and %v", completion, err)
+	}
+	if completion.Text != "Pikachu" || completion.Usage != (types.Usage{PromptTokens: 4, CompletionTokens: 2, TotalTokens: 6}) {
+		t.Errorf("Unexpected partial completion %+v", completion)
+	}
+
+	// So is a stream that breaks after some text
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Pikachu\"}}]}\n\ndata: {\"choices\n\n")
+	}))
+	defer server.Close()
+
+	completion, err = NewOpenAIProvider(newOpenAIConfig(server.URL)).Stream(context.Background(), prompt, func(string) error { return nil })
+	if !errors.Is(err, ErrDecode) || completion == nil || completion.Usage.CompletionTokens != 2 {
+		t.Errorf("Expected the partial completion with ErrDecode, got %+v and %v", completion, err)
+	}
+
+	// Requests the provider rejects cost nothing
+	fake.fail(http.StatusTooManyRequests)
+	if completion, _ := provider.Stream(context.Background(), prompt, func(string) error { return nil }); completion != nil {
+		t.Errorf("Expected no completion, got %+v", completion)
+	}
+}
+
+func TestCompatibleProvider_StreamWithoutUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"choices\": [{\"delta\": {\"content\": \"Pikachu is Electric.\"}}]}\n\ndata: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := NewCompatibleProvider(&config.Config{CompatibleBaseURL: server.URL, CompatibleModel: "llama3.1"})
+	prompt := Prompt{Messages: []types.ChatMessage{{Role: "user", Content: "Explain Pikachu"}}}
+
+	completion, err := provider.Stream(context.Background(), prompt, func(string) error { return nil })
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if completion.Usage != (types.Usage{PromptTokens: 4, CompletionTokens: 5, TotalTokens: 9}) {
+		t.Errorf("Expected an estimate of the usage, got %+v", completion.Usage)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// pokemonAPIService names the Pokémon API in errors
+const pokemonAPIService = "pokemon-api"
+
+// PokemonSource represents any provider of the Pokémon data explained by the AI
+type PokemonSource interface {
+	GetPokemon(ctx context.Context, id int, lang string) (*types.PokemonResponse, error)
+	GetSpecies(ctx context.Context, id int, lang string) (*types.SpeciesResponse, error)
+}
+
+// PokemonClient fetches Pokémon from the Pokémon API service, which wraps
+// the PokeAPI with caching, retries and localization
+type PokemonClient struct {
+	baseURL    string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// Ensure the client satisfies the interface
+var _ PokemonSource = (*PokemonClient)(nil)
+
+// NewPokemonClient creates a new instance of the client
+func NewPokemonClient(cfg *config.Config) *PokemonClient {
+	return &PokemonClient{
+		baseURL:    cfg.APIBaseURL,
+		httpClient: &http.Client{},
+		timeout:    cfg.APITimeout,
+	}
+}
+
+// GetPokemon searches for a Pokémon by ID, with its name in the language
+func (c *PokemonClient) GetPokemon(ctx context.Context, id int, lang string) (*types.PokemonResponse, error) {
+	var pokemon types.PokemonResponse
+	if err := c.get(ctx, fmt.Sprintf("/pokemon/id/%d", id), lang, &pokemon); err != nil {
+		return nil, err
+	}
+	return &pokemon, nil
+}
+
+// GetSpecies searches for the species of a Pokémon by ID, with its texts in the language
+func (c *PokemonClient) GetSpecies(ctx context.Context, id int, lang string) (*types.SpeciesResponse, error) {
+	var species types.SpeciesResponse
+	if err := c.get(ctx, fmt.Sprintf("/pokemon/id/%d/species", id), lang, &species); err != nil {
+		return nil, err
+	}
+	return &species, nil
+}
+
+// get requests the path from the Pokémon API and decodes the data of the response envelope
+func (c *PokemonClient) get(ctx context.Context, path, lang string, out any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	if lang != "" {
+		path += "?lang=" + url.QueryEscape(lang)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &UpstreamError{Service: pokemonAPIService, Path: path, Kind: ErrUpstreamUnavailable, Cause: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &UpstreamError{Service: pokemonAPIService, Path: path, StatusCode: resp.StatusCode, Kind: ErrUpstreamUnavailable, Cause: err}
+	}
+
+	var envelope types.APIResponse
+	decodeErr := json.Unmarshal(body, &envelope)
+
+	if resp.StatusCode != http.StatusOK {
+		upstreamErr := &UpstreamError{Service: pokemonAPIService, Path: path, StatusCode: resp.StatusCode, Kind: kindForStatus(resp.StatusCode)}
+		if decodeErr == nil && envelope.Error != nil {
+			upstreamErr.Code = envelope.Error.Code
+			upstreamErr.Message = envelope.Error.Message
+		}
+		return upstreamErr
+	}
+
+	if decodeErr != nil {
+		return &UpstreamError{Service: pokemonAPIService, Path: path, StatusCode: resp.StatusCode, Kind: ErrDecode, Cause: decodeErr}
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return &UpstreamError{Service: pokemonAPIService, Path: path, StatusCode: resp.StatusCode, Kind: ErrDecode, Cause: err}
+	}
+
+	return nil
+}
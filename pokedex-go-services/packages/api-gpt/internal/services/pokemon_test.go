@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pokedexia-gpt/internal/config"
+)
+
+// newFakeAPI is a local Pokémon API that serves Pikachu and its species
+func newFakeAPI() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lang := r.URL.Query().Get("lang"); lang == "xx" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "error": {"code": "UNSUPPORTED_LANGUAGE", "message": "Unsupported language: xx"}}`)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/pokemon/id/25":
+			fmt.Fprint(w, `{"success": true, "data": {"id": 25, "name": "pikachu", "localized_name": "Pikachu", "types": ["electric"],
+				"abilities": ["static", "lightning-rod"], "height": 4, "weight": 60,
+				"stats": {"hp": 35, "attack": 55, "defense": 40, "special_attack": 50, "special_defense": 50, "speed": 90}}}`)
+		case "/pokemon/id/25/species":
+			fmt.Fprint(w, `{"success": true, "data": {"id": 25, "name": "pikachu", "generation": "generation-i", "evolves_from": "pichu",
+				"localized": {"name": "Pikachu", "genus": "Mouse Pokémon", "flavor_text": [{"version": "red", "text": "When several of these POKéMON gather, their electricity could build and cause lightning storms."}]}}}`)
+		case "/pokemon/id/10100":
+			fmt.Fprint(w, `{"success": true, "data": {"id": 10100, "name": "raichu-alola", "types": ["electric", "psychic"]}}`)
+		case "/pokemon/id/500":
+			fmt.Fprint(w, `{"success": true, "data": `)
+		case "/pokemon/id/503":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"success": false, "error": {"code": "UPSTREAM_UNAVAILABLE", "message": "PokeAPI is unavailable"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"success": false, "error": {"code": "POKEMON_NOT_FOUND", "message": "Pokémon not found"}}`)
+		}
+	}))
+}
+
+func TestPokemonClient_GetPokemon(t *testing.T) {
+	server := newFakeAPI()
+	defer server.Close()
+
+	client := NewPokemonClient(&config.Config{APIBaseURL: server.URL})
+
+	pokemon, err := client.GetPokemon(context.Background(), 25, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.Name != "pikachu" || pokemon.Stats.Speed != 90 || len(pokemon.Abilities) != 2 {
+		t.Errorf("Unexpected Pokémon %+v", pokemon)
+	}
+
+	species, err := client.GetSpecies(context.Background(), 25, "en")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if species.Localized == nil || species.Localized.Genus != "Mouse Pokémon" {
+		t.Errorf("Unexpected species %+v", species)
+	}
+}
+
+func TestPokemonClient_Errors(t *testing.T) {
+	server := newFakeAPI()
+	defer server.Close()
+
+	client := NewPokemonClient(&config.Config{APIBaseURL: server.URL})
+
+	tests := []struct {
+		id   int
+		lang string
+		kind error
+	}{
+		{150, "", ErrNotFound},
+		{25, "xx", ErrInvalidRequest},
+		{500, "", ErrDecode},
+		{503, "", ErrUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		_, err := client.GetPokemon(context.Background(), tt.id, tt.lang)
+		if !errors.Is(err, tt.kind) {
+			t.Errorf("GetPokemon(%d, %q) error = %v, expected %v", tt.id, tt.lang, err, tt.kind)
+		}
+	}
+
+	// The error of the Pokémon API is kept for the client
+	_, err := client.GetPokemon(context.Background(), 25, "xx")
+	var upstreamErr *UpstreamError
+	if !errors.As(err, &upstreamErr) || upstreamErr.Code != "UNSUPPORTED_LANGUAGE" || upstreamErr.Message != "Unsupported language: xx" {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	server.Close()
+	if _, err := client.GetPokemon(context.Background(), 25, ""); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// Provider names accepted by LLM_PROVIDER
+const (
+	ProviderOpenAI     = "openai"
+	ProviderCompatible = "compatible"
+	ProviderTemplate   = "template"
+)
+
+// ErrUnknownProvider means the configuration selects a provider that does not exist
+var ErrUnknownProvider = errors.New("unknown LLM provider")
+
+// LLMProvider generates the text of explanations
+type LLMProvider interface {
+	// Name returns the provider name, e.g. "openai"
+	Name() string
+	// Model returns the model used when the provider does not report one
+	Model() string
+	// Configured reports whether the provider can generate text
+	Configured() bool
+	// Generate generates the explanation asked for by the prompt. When it
+	// fails after the provider took the request, it also returns the
+	// completion so far, whose usage is billed anyway.
+	Generate(ctx context.Context, prompt Prompt) (*Completion, error)
+	// Stream generates the explanation like Generate, passing each piece of
+	// text to onToken as soon as it is available. It stops with the error
+	// of onToken when it returns one.
+	Stream(ctx context.Context, prompt Prompt, onToken TokenFunc) (*Completion, error)
+}
+
+// TokenFunc receives the pieces of a streamed explanation in order
+type TokenFunc func(token string) error
+
+// Prompt represents a request for the explanation of a Pokémon. Language
+// models answer the messages; the template provider uses the Pokémon data.
+type Prompt struct {
+	Messages []types.ChatMessage
+	Pokemon  *types.PokemonResponse
+	Species  *types.SpeciesResponse // Nil when the Pokémon has no species data
+	Language string
+	Persona  string
+}
+
+// Completion represents the text generated for a prompt
+type Completion struct {
+	Text  string
+	Model string
+	Usage types.Usage
+}
+
+// NewProvider creates the provider selected by the configuration
+func NewProvider(cfg *config.Config) (LLMProvider, error) {
+	switch cfg.LLMProvider {
+	case ProviderOpenAI:
+		return NewOpenAIProvider(cfg), nil
+	case ProviderCompatible:
+		return NewCompatibleProvider(cfg), nil
+	case ProviderTemplate:
+		return NewTemplateProvider(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, cfg.LLMProvider)
+	}
+}
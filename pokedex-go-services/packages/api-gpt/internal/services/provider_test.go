@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"pokedexia-gpt/internal/config"
+)
+
+func TestNewProvider(t *testing.T) {
+	testCases := []struct {
+		provider string
+		name     string
+		model    string
+	}{
+		{ProviderOpenAI, ProviderOpenAI, "gpt-4o-mini"},
+		{ProviderCompatible, ProviderCompatible, "llama3.1"},
+		{ProviderTemplate, ProviderTemplate, templateModel},
+	}
+
+	for _, tc := range testCases {
+		cfg := &config.Config{LLMProvider: tc.provider, OpenAIModel: "gpt-4o-mini", CompatibleModel: "llama3.1"}
+
+		provider, err := NewProvider(cfg)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.provider, err)
+		}
+		if provider.Name() != tc.name || provider.Model() != tc.model {
+			t.Errorf("%s: unexpected provider %s (%s)", tc.provider, provider.Name(), provider.Model())
+		}
+	}
+
+	if _, err := NewProvider(&config.Config{LLMProvider: "gemini"}); !errors.Is(err, ErrUnknownProvider) {
+		t.Errorf("Expected ErrUnknownProvider, got %v", err)
+	}
+}
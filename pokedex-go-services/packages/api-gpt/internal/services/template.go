@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"pokedexia-gpt/internal/types"
+)
+
+// templateModelPrefix starts the model name of the template provider, which
+// is followed by the version of the templates
+const templateModelPrefix = "template-"
+
+// statNames names the base stats in each template language, in the order
+// ties between stats are broken
+var statNames = map[string][6]string{
+	"en":    {"HP", "Attack", "Defense", "Special Attack", "Special Defense", "Speed"},
+	"pt-BR": {"PS", "Ataque", "Defesa", "Ataque Especial", "Defesa Especial", "Velocidade"},
+	"es":    {"PS", "Ataque", "Defensa", "Ataque Especial", "Defensa Especial", "Velocidad"},
+}
+
+// templateSources holds the explanation template of each language
+var templateSources = map[string]string{
+	"en": `{{.Name}} is Pokémon #{{.ID}} of the National Pokédex, of type {{.Types}}{{if .Genus}}, known as the {{.Genus}}{{end}}. ` +
+		`It is {{.Height}} m tall and weighs {{.Weight}} kg.` +
+		`{{if .Legendary}} It is a Legendary Pokémon.{{end}}{{if .Mythical}} It is a Mythical Pokémon.{{end}}` +
+		`{{if .EvolvesFrom}} It evolves from {{.EvolvesFrom}}.{{end}}` + "\n\n" +
+		`Its strongest base stat is {{.Strongest.Name}} ({{.Strongest.Value}}) and its weakest is {{.Weakest.Name}} ({{.Weakest.Value}}), ` +
+		`for a total of {{.Total}}.{{if .Abilities}} Its abilities are {{.Abilities}}.{{end}}` +
+		`{{if .Entry}}` + "\n\n" + `Pokédex: {{.Entry}}{{end}}`,
+	"pt-BR": `{{.Name}} é o Pokémon nº {{.ID}} da Pokédex Nacional, do tipo {{.Types}}{{if .Genus}}, conhecido como {{.Genus}}{{end}}. ` +
+		`Mede {{.Height}} m e pesa {{.Weight}} kg.` +
+		`{{if .Legendary}} É um Pokémon Lendário.{{end}}{{if .Mythical}} É um Pokémon Mítico.{{end}}` +
+		`{{if .EvolvesFrom}} Evolui de {{.EvolvesFrom}}.{{end}}` + "\n\n" +
+		`Seu atributo base mais forte é {{.Strongest.Name}} ({{.Strongest.Value}}) e o mais fraco é {{.Weakest.Name}} ({{.Weakest.Value}}), ` +
+		`com um total de {{.Total}}.{{if .Abilities}} Suas habilidades são {{.Abilities}}.{{end}}` +
+		`{{if .Entry}}` + "\n\n" + `Pokédex: {{.Entry}}{{end}}`,
+	"es": `{{.Name}} es el Pokémon n.º {{.ID}} de la Pokédex Nacional, de tipo {{.Types}}{{if .Genus}}, conocido como {{.Genus}}{{end}}. ` +
+		`Mide {{.Height}} m y pesa {{.Weight}} kg.` +
+		`{{if .Legendary}} Es un Pokémon Legendario.{{end}}{{if .Mythical}} Es un Pokémon Singular.{{end}}` +
+		`{{if .EvolvesFrom}} Evoluciona de {{.EvolvesFrom}}.{{end}}` + "\n\n" +
+		`Su estadística base más alta es {{.Strongest.Name}} ({{.Strongest.Value}}) y la más baja es {{.Weakest.Name}} ({{.Weakest.Value}}), ` +
+		`con un total de {{.Total}}.{{if .Abilities}} Sus habilidades son {{.Abilities}}.{{end}}` +
+		`{{if .Entry}}` + "\n\n" + `Pokédex: {{.Entry}}{{end}}`,
+}
+
+// explanationTemplates holds the parsed template of each language
+var explanationTemplates = parseTemplates()
+
+// templateModel is the model name of the template provider. Its version is a
+// hash of the templates and the stat names, so editing them gives new cache
+// keys and stored explanations are written again.
+var templateModel = newTemplateModel()
+
+// TemplateProvider builds explanations from the Pokémon data with fixed
+// templates. It needs no network access and always returns the same text
+// for the same data, which suits CI and offline demos.
+type TemplateProvider struct{}
+
+// Ensure the provider satisfies the interface
+var _ LLMProvider = (*TemplateProvider)(nil)
+
+// NewTemplateProvider creates a new instance of the provider
+func NewTemplateProvider() *TemplateProvider {
+	return &TemplateProvider{}
+}
+
+// Name returns the provider name
+func (p *TemplateProvider) Name() string {
+	return ProviderTemplate
+}
+
+// Model returns the version of the templates
+func (p *TemplateProvider) Model() string {
+	return templateModel
+}
+
+// Configured reports that the provider is always available
+func (p *TemplateProvider) Configured() bool {
+	return true
+}
+
+// Generate fills the template of the prompt language, or the English one
+// when the language has none, with the Pokémon data
+func (p *TemplateProvider) Generate(ctx context.Context, prompt Prompt) (*Completion, error) {
+	if prompt.Pokemon == nil {
+		return nil, errors.New("template provider: the prompt has no Pokémon")
+	}
+
+	lang := prompt.Language
+	if _, ok := explanationTemplates[lang]; !ok {
+		lang = DefaultLanguage
+	}
+
+	var text strings.Builder
+	if err := explanationTemplates[lang].Execute(&text, newTemplateData(prompt.Pokemon, prompt.Species, lang)); err != nil {
+		return nil, fmt.Errorf("template provider: %w", err)
+	}
+
+	return &Completion{
+		Text:  text.String(),
+		Model: templateModel,
+	}, nil
+}
+
+// Stream generates the explanation like Generate and passes it on word by word
+func (p *TemplateProvider) Stream(ctx context.Context, prompt Prompt, onToken TokenFunc) (*Completion, error) {
+	completion, err := p.Generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, token := range strings.SplitAfter(completion.Text, " ") {
+		if err := onToken(token); err != nil {
+			return nil, err
+		}
+	}
+	return completion, nil
+}
+
+// parseTemplates parses the template of each language
+func parseTemplates() map[string]*template.Template {
+	templates := make(map[string]*template.Template, len(templateSources))
+	for lang, source := range templateSources {
+		templates[lang] = template.Must(template.New(lang).Parse(source))
+	}
+	return templates
+}
+
+// newTemplateModel returns the model name with the hash of the templates and
+// the stat names of every language
+func newTemplateModel() string {
+	langs := make([]string, 0, len(templateSources))
+	for lang := range templateSources {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	hash := sha256.New()
+	for _, lang := range langs {
+		names := statNames[lang]
+		fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", lang, templateSources[lang], strings.Join(names[:], "\x00"))
+	}
+	return templateModelPrefix + hex.EncodeToString(hash.Sum(nil))[:promptVersionLength]
+}
+
+// templateStat represents a base stat in a template
+type templateStat struct {
+	Name  string
+	Value int
+}
+
+// templateData represents the values available to the templates
+type templateData struct {
+	Name        string
+	ID          int
+	Types       string
+	Abilities   string
+	Height      string
+	Weight      string
+	Strongest   templateStat
+	Weakest     templateStat
+	Total       int
+	Genus       string
+	Legendary   bool
+	Mythical    bool
+	EvolvesFrom string
+	Entry       string
+}
+
+// newTemplateData returns the template values of a Pokémon. The species is optional.
+func newTemplateData(pokemon *types.PokemonResponse, species *types.SpeciesResponse, lang string) templateData {
+	stats := pokemon.Stats
+	values := [6]int{stats.HP, stats.Attack, stats.Defense, stats.SpecialAttack, stats.SpecialDefense, stats.Speed}
+	names := statNames[lang]
+
+	data := templateData{
+		Name:      displayName(pokemon),
+		ID:        pokemon.ID,
+		Types:     strings.Join(pokemon.Types, "/"),
+		Abilities: strings.Join(pokemon.Abilities, ", "),
+		Height:    fmt.Sprintf("%.1f", float64(pokemon.Height)/10),
+		Weight:    fmt.Sprintf("%.1f", float64(pokemon.Weight)/10),
+		Strongest: templateStat{Name: names[0], Value: values[0]},
+		Weakest:   templateStat{Name: names[0], Value: values[0]},
+	}
+
+	for i, value := range values {
+		data.Total += value
+		if value > data.Strongest.Value {
+			data.Strongest = templateStat{Name: names[i], Value: value}
+		}
+		if value < data.Weakest.Value {
+			data.Weakest = templateStat{Name: names[i], Value: value}
+		}
+	}
+
+	if species != nil {
+		data.Legendary = species.IsLegendary
+		data.Mythical = species.IsMythical
+		data.EvolvesFrom = species.EvolvesFrom
+		if species.Localized != nil {
+			data.Genus = species.Localized.Genus
+			if len(species.Localized.FlavorText) > 0 {
+				data.Entry = species.Localized.FlavorText[0].Text
+			}
+		}
+	}
+
+	return data
+}
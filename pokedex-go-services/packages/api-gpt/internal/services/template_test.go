@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"pokedexia-gpt/internal/types"
+)
+
+func newTemplatePrompt(lang string) Prompt {
+	return Prompt{
+		Pokemon: &types.PokemonResponse{
+			ID: 25, Name: "pikachu", LocalizedName: "Pikachu", Types: []string{"electric"},
+			Abilities: []string{"static", "lightning-rod"}, Height: 4, Weight: 60,
+			Stats: types.Stats{HP: 35, Attack: 55, Defense: 40, SpecialAttack: 50, SpecialDefense: 50, Speed: 90},
+		},
+		Species: &types.SpeciesResponse{
+			EvolvesFrom: "pichu",
+			Localized: &types.LocalizedSpecies{
+				Genus:      "Mouse Pokémon",
+				FlavorText: []types.FlavorText{{Version: "red", Text: "It keeps its tail raised to monitor its surroundings."}},
+			},
+		},
+		Language: lang,
+	}
+}
+
+func TestTemplateProvider_Generate(t *testing.T) {
+	provider := NewTemplateProvider()
+
+	completion, err := provider.Generate(context.Background(), newTemplatePrompt("en"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "Pikachu is Pokémon #25 of the National Pokédex, of type electric, known as the Mouse Pokémon. " +
+		"It is 0.4 m tall and weighs 6.0 kg. It evolves from pichu.\n\n" +
+		"Its strongest base stat is Speed (90) and its weakest is HP (35), for a total of 320. " +
+		"Its abilities are static, lightning-rod.\n\n" +
+		"Pokédex: It keeps its tail raised to monitor its surroundings."
+	if completion.Text != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, completion.Text)
+	}
+	if completion.Model != templateModel || completion.Usage.TotalTokens != 0 {
+		t.Errorf("Unexpected completion %+v", completion)
+	}
+
+	// The same data always produces the same text
+	again, _ := provider.Generate(context.Background(), newTemplatePrompt("en"))
+	if again.Text != completion.Text {
+		t.Error("Expected the same text for the same data")
+	}
+}
+
+func TestTemplateProvider_Languages(t *testing.T) {
+	testCases := []struct {
+		lang     string
+		expected string
+	}{
+		{"pt-BR", "Seu atributo base mais forte é Velocidade (90)"},
+		{"es", "Su estadística base más alta es Velocidad (90)"},
+		{"ja", "Its strongest base stat is Speed (90)"},
+	}
+
+	for _, tc := range testCases {
+		completion, err := NewTemplateProvider().Generate(context.Background(), newTemplatePrompt(tc.lang))
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.lang, err)
+		}
+		if !strings.Contains(completion.Text, tc.expected) {
+			t.Errorf("%s: expected %q in:\n%s", tc.lang, tc.expected, completion.Text)
+		}
+	}
+}
+
+func TestTemplateProvider_WithoutSpecies(t *testing.T) {
+	prompt := newTemplatePrompt("en")
+	prompt.Species = nil
+	prompt.Pokemon.Abilities = nil
+
+	completion, err := NewTemplateProvider().Generate(context.Background(), prompt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, missing := range []string{"known as", "evolves", "abilities", "Pokédex:"} {
+		if strings.Contains(completion.Text, missing) {
+			t.Errorf("Expected no %q without the data:\n%s", missing, completion.Text)
+		}
+	}
+
+	if _, err := NewTemplateProvider().Generate(context.Background(), Prompt{}); err == nil {
+		t.Error("Expected an error without a Pokémon")
+	}
+}
+
+func TestTemplateProvider_Stream(t *testing.T) {
+	provider := NewTemplateProvider()
+
+	var text strings.Builder
+	completion, err := provider.Stream(context.Background(), newTemplatePrompt("en"), func(token string) error {
+		text.WriteString(token)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected, _ := provider.Generate(context.Background(), newTemplatePrompt("en"))
+	if text.String() != expected.Text || completion.Text != expected.Text {
+		t.Errorf("Expected the streamed text to match the generated one, got:\n%s", text.String())
+	}
+}
+
+func TestTemplateModel(t *testing.T) {
+	if !strings.HasPrefix(templateModel, templateModelPrefix) || len(templateModel) != len(templateModelPrefix)+promptVersionLength {
+		t.Errorf("Unexpected model %q", templateModel)
+	}
+	if newTemplateModel() != templateModel {
+		t.Error("Expected a stable model")
+	}
+
+	key := CacheKey{PokemonID: 25, Language: "en", Persona: DefaultPersona, PromptVersion: promptVersions[DefaultPersona], Model: templateModel}
+
+	// Editing a template gives a new version, and so a new cache key
+	source := templateSources["es"]
+	templateSources["es"] = source + " ¡Hasta pronto!"
+	defer func() { templateSources["es"] = source }()
+
+	edited := key
+	edited.Model = newTemplateModel()
+	if edited.Model == templateModel || edited.String() == key.String() {
+		t.Errorf("Expected a new cache key when a template changes, got %s", edited)
+	}
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/store"
+	"pokedexia-gpt/internal/types"
+)
+
+// usageCurrency is the currency of prices, budgets and costs
+const usageCurrency = "USD"
+
+// tokensPerPrice is the number of tokens prices refer to
+const tokensPerPrice = 1_000_000
+
+// Budget periods
+const (
+	periodDaily   = "daily"
+	periodMonthly = "monthly"
+)
+
+// scopeGlobal identifies the budgets shared by every client
+const scopeGlobal = "global"
+
+// BudgetError reports the budget that is exhausted
+type BudgetError struct {
+	Scope  string // "global" or the client ID
+	Period string
+	Budget float64
+	Spent  float64
+}
+
+// Error returns the error message
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("%s %s budget of %.2f %s exhausted (%.4f spent)", e.Scope, e.Period, e.Budget, usageCurrency, e.Spent)
+}
+
+// Unwrap returns the sentinel error
+func (e *BudgetError) Unwrap() error {
+	return ErrBudgetExhausted
+}
+
+// UsageTracker records the tokens and the cost of each generation and
+// enforces daily and monthly budgets, globally and per client. With a
+// store, the totals survive restarts.
+type UsageTracker struct {
+	mu       sync.Mutex
+	prices   map[string]config.Price
+	budgets  map[string]budget
+	store    store.Store
+	day      *types.PeriodUsage
+	month    *types.PeriodUsage
+	unpriced map[string]bool
+	now      func() time.Time
+}
+
+// budget represents the global and per-client budgets of a period
+type budget struct {
+	global float64
+	client float64
+}
+
+// NewUsageTracker creates a new instance of the tracker
+func NewUsageTracker(cfg *config.Config) *UsageTracker {
+	return &UsageTracker{
+		prices: cfg.AIPrices,
+		budgets: map[string]budget{
+			periodDaily:   {global: cfg.AIDailyBudget, client: cfg.AIKeyDailyBudget},
+			periodMonthly: {global: cfg.AIMonthlyBudget, client: cfg.AIKeyMonthlyBudget},
+		},
+		unpriced: make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+// ValidatePrices returns ErrUnpricedModel when budgets are set but the model
+// of the provider has no price, since its generations would never count
+// towards them. Free models need an explicit price of 0/0.
+func ValidatePrices(cfg *config.Config, provider LLMProvider) error {
+	budgeted := cfg.AIDailyBudget > 0 || cfg.AIMonthlyBudget > 0 || cfg.AIKeyDailyBudget > 0 || cfg.AIKeyMonthlyBudget > 0
+	if !budgeted || provider.Name() == ProviderTemplate {
+		return nil
+	}
+	if _, ok := lookupPrice(cfg.AIPrices, provider.Model()); !ok {
+		return fmt.Errorf("%w: set the price of %q in AI_PRICES", ErrUnpricedModel, provider.Model())
+	}
+	return nil
+}
+
+// WithStore sets the store where the usage totals are persisted
+func (t *UsageTracker) WithStore(st store.Store) *UsageTracker {
+	t.store = st
+	return t
+}
+
+// Allow returns a *BudgetError when a budget of the client is exhausted.
+// Budgets are checked before each generation, so concurrent generations
+// may exceed them by their own cost.
+func (t *UsageTracker) Allow(client string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, period := range t.current() {
+		limits := t.budgets[period.kind]
+		if exhausted(limits.global, period.usage.Total.Cost) {
+			return &BudgetError{Scope: scopeGlobal, Period: period.kind, Budget: limits.global, Spent: period.usage.Total.Cost}
+		}
+		if spent := period.usage.Clients[client].Cost; exhausted(limits.client, spent) {
+			return &BudgetError{Scope: client, Period: period.kind, Budget: limits.client, Spent: spent}
+		}
+	}
+	return nil
+}
+
+// Record adds the tokens of a generation for the client to the totals and
+// returns its cost
+func (t *UsageTracker) Record(client, model string, usage types.Usage) float64 {
+	cost := t.Cost(model, usage)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Providers may report a model other than the configured one
+	if _, ok := lookupPrice(t.prices, model); !ok && !strings.HasPrefix(model, templateModelPrefix) && !t.unpriced[model] {
+		t.unpriced[model] = true
+		log.Printf("The %s model has no price in AI_PRICES, its generations cost nothing towards the budgets", model)
+	}
+
+	for _, period := range t.current() {
+		add(&period.usage.Total, usage, cost)
+
+		totals := period.usage.Clients[client]
+		add(&totals, usage, cost)
+		period.usage.Clients[client] = totals
+
+		t.persist(period.kind, period.usage)
+	}
+
+	return cost
+}
+
+// Cost returns the cost of the tokens with the price of the model. Models
+// are matched by their longest priced prefix, so "gpt-4o-mini-2024-07-18"
+// has the price of "gpt-4o-mini". Models without a price cost nothing.
+func (t *UsageTracker) Cost(model string, usage types.Usage) float64 {
+	price, _ := lookupPrice(t.prices, model)
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / tokensPerPrice
+}
+
+// Report returns the usage of the current day and month
+func (t *UsageTracker) Report() types.UsageReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	periods := t.current()
+	return types.UsageReport{
+		Currency: usageCurrency,
+		Day:      t.snapshot(periods[0]),
+		Month:    t.snapshot(periods[1]),
+	}
+}
+
+// trackedPeriod represents the usage of the current day or month
+type trackedPeriod struct {
+	kind  string
+	usage *types.PeriodUsage
+}
+
+// current returns the usage of the current day and month, in this order,
+// loading them from the store when the period changes. The caller holds the lock.
+func (t *UsageTracker) current() []trackedPeriod {
+	now := t.now().UTC()
+
+	if period := now.Format("2006-01-02"); t.day == nil || t.day.Period != period {
+		t.day = t.load(periodDaily, period)
+	}
+	if period := now.Format("2006-01"); t.month == nil || t.month.Period != period {
+		t.month = t.load(periodMonthly, period)
+	}
+	return []trackedPeriod{{periodDaily, t.day}, {periodMonthly, t.month}}
+}
+
+// snapshot returns a copy of the usage of a period with its budgets
+func (t *UsageTracker) snapshot(period trackedPeriod) types.PeriodUsage {
+	copied := *period.usage
+	copied.Budget = t.budgets[period.kind].global
+	copied.KeyBudget = t.budgets[period.kind].client
+	copied.Clients = make(map[string]types.UsageTotals, len(period.usage.Clients))
+	for client, totals := range period.usage.Clients {
+		copied.Clients[client] = totals
+	}
+	return copied
+}
+
+// load returns the usage of a period from the store, or an empty one
+func (t *UsageTracker) load(kind, period string) *types.PeriodUsage {
+	usage := &types.PeriodUsage{Period: period, Clients: make(map[string]types.UsageTotals)}
+	if t.store == nil {
+		return usage
+	}
+
+	key := usageKey(kind, period)
+	body, err := t.store.Get(key)
+	if err != nil {
+		if !errors.Is(err, store.ErrNotFound) {
+			log.Printf("Error reading %s from the store: %v", key, err)
+		}
+		return usage
+	}
+
+	if err := json.Unmarshal(body, usage); err != nil {
+		log.Printf("Error decoding %s from the store: %v", key, err)
+		return &types.PeriodUsage{Period: period, Clients: make(map[string]types.UsageTotals)}
+	}
+	if usage.Clients == nil {
+		usage.Clients = make(map[string]types.UsageTotals)
+	}
+	return usage
+}
+
+// persist writes the usage of a period to the store. Failures are logged
+// and never fail the request.
+func (t *UsageTracker) persist(kind string, usage *types.PeriodUsage) {
+	if t.store == nil {
+		return
+	}
+
+	key := usageKey(kind, usage.Period)
+
+	body, err := json.Marshal(usage)
+	if err != nil {
+		log.Printf("Error encoding %s for the store: %v", key, err)
+		return
+	}
+	if err := t.store.Put(key, body); err != nil {
+		log.Printf("Error writing %s to the store: %v", key, err)
+	}
+}
+
+// lookupPrice returns the price of the longest priced prefix of the model
+func lookupPrice(prices map[string]config.Price, model string) (config.Price, bool) {
+	var price config.Price
+	var matched string
+	for name, p := range prices {
+		if strings.HasPrefix(model, name) && len(name) > len(matched) {
+			price, matched = p, name
+		}
+	}
+	return price, matched != ""
+}
+
+// usageKey returns the store key of the usage of a period
+func usageKey(kind, period string) string {
+	return "usage/" + kind + "/" + period
+}
+
+// exhausted reports whether the spending reached the budget. A budget of zero is unlimited.
+func exhausted(budget, spent float64) bool {
+	return budget > 0 && spent >= budget
+}
+
+// add adds the tokens and the cost of a generation to the totals
+func add(totals *types.UsageTotals, usage types.Usage, cost float64) {
+	totals.Requests++
+	totals.PromptTokens += usage.PromptTokens
+	totals.CompletionTokens += usage.CompletionTokens
+	totals.Cost += cost
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/types"
+)
+
+// testUsage is the usage reported by the fake OpenAI server
+var testUsage = types.Usage{PromptTokens: 120, CompletionTokens: 80, TotalTokens: 200}
+
+// testCost is the cost of testUsage with the gpt-4o-mini price
+const testCost = (120*0.15 + 80*0.60) / 1_000_000
+
+func newTestUsageTracker(cfg *config.Config, now *time.Time) *UsageTracker {
+	if cfg.AIPrices == nil {
+		cfg.AIPrices = map[string]config.Price{
+			"gpt-4o-mini": {Prompt: 0.15, Completion: 0.60},
+			"gpt-4o":      {Prompt: 2.50, Completion: 10.00},
+		}
+	}
+
+	tracker := NewUsageTracker(cfg)
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func equalCost(a, b float64) bool {
+	return math.Abs(a-b) < 1e-12
+}
+
+func TestUsageTracker_Cost(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newTestUsageTracker(&config.Config{}, &now)
+
+	testCases := []struct {
+		model string
+		cost  float64
+	}{
+		{"gpt-4o-mini", testCost},
+		{"gpt-4o-mini-2024-07-18", testCost},
+		{"gpt-4o-2024-08-06", (120*2.50 + 80*10.00) / 1_000_000},
+		{"llama3.1", 0},
+		{templateModel, 0},
+	}
+
+	for _, tc := range testCases {
+		if cost := tracker.Cost(tc.model, testUsage); !equalCost(cost, tc.cost) {
+			t.Errorf("%s: expected %g, got %g", tc.model, tc.cost, cost)
+		}
+	}
+}
+
+func TestValidatePrices(t *testing.T) {
+	prices := map[string]config.Price{"gpt-4o-mini": {Prompt: 0.15, Completion: 0.60}, "llama3.1": {}}
+
+	testCases := []struct {
+		name     string
+		cfg      *config.Config
+		provider string
+		model    string
+		valid    bool
+	}{
+		{"priced model", &config.Config{AIDailyBudget: 1}, ProviderOpenAI, "gpt-4o-mini", true},
+		{"free model", &config.Config{AIKeyMonthlyBudget: 1}, ProviderCompatible, "llama3.1", true},
+		{"unpriced model", &config.Config{AIKeyDailyBudget: 1}, ProviderCompatible, "qwen2.5", false},
+		{"unpriced model without budgets", &config.Config{}, ProviderCompatible, "qwen2.5", true},
+		{"template provider", &config.Config{AIMonthlyBudget: 1}, ProviderTemplate, templateModel, true},
+	}
+
+	for _, tc := range testCases {
+		tc.cfg.AIPrices = prices
+		tc.cfg.LLMProvider = tc.provider
+		tc.cfg.OpenAIModel = tc.model
+		tc.cfg.CompatibleModel = tc.model
+
+		provider, err := NewProvider(tc.cfg)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.name, err)
+		}
+		if err := ValidatePrices(tc.cfg, provider); (err == nil) != tc.valid || (err != nil && !errors.Is(err, ErrUnpricedModel)) {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+	}
+}
+
+func TestUsageTracker_Record(t *testing.T) {
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	tracker := newTestUsageTracker(&config.Config{AIDailyBudget: 5, AIKeyMonthlyBudget: 10}, &now)
+
+	alice, bob := ClientID("alice"), ClientID("bob")
+	if cost := tracker.Record(alice, "gpt-4o-mini", testUsage); !equalCost(cost, testCost) {
+		t.Errorf("Expected %g, got %g", testCost, cost)
+	}
+	tracker.Record(alice, "gpt-4o-mini", testUsage)
+	tracker.Record(bob, "gpt-4o-mini", testUsage)
+
+	report := tracker.Report()
+	if report.Currency != "USD" || report.Day.Period != "2024-05-31" || report.Month.Period != "2024-05" {
+		t.Errorf("Unexpected report periods %+v", report)
+	}
+	if report.Day.Budget != 5 || report.Month.KeyBudget != 10 {
+		t.Errorf("Expected the budgets in the report, got %+v", report)
+	}
+
+	total := report.Day.Total
+	if total.Requests != 3 || total.PromptTokens != 360 || total.CompletionTokens != 240 || !equalCost(total.Cost, 3*testCost) {
+		t.Errorf("Unexpected day totals %+v", total)
+	}
+	if report.Day.Clients[alice].Requests != 2 || report.Day.Clients[bob].Requests != 1 {
+		t.Errorf("Unexpected client totals %+v", report.Day.Clients)
+	}
+
+	// The report is a copy
+	report.Day.Clients[alice] = types.UsageTotals{}
+	if tracker.Report().Day.Clients[alice].Requests != 2 {
+		t.Error("Expected the report to not change the totals")
+	}
+
+	// A new day starts from zero, the month goes on until it ends
+	now = now.Add(24 * time.Hour)
+	report = tracker.Report()
+	if report.Day.Period != "2024-06-01" || report.Day.Total.Requests != 0 || report.Month.Total.Requests != 0 {
+		t.Errorf("Expected new periods, got %+v", report)
+	}
+
+	now = time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC)
+	tracker.Record(alice, "gpt-4o-mini", testUsage)
+	now = time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	if report := tracker.Report(); report.Day.Total.Requests != 0 || report.Month.Total.Requests != 1 {
+		t.Errorf("Expected the month to keep its totals, got %+v", report)
+	}
+}
+
+func TestUsageTracker_Allow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	alice, bob := ClientID("alice"), ClientID("bob")
+
+	testCases := []struct {
+		name   string
+		cfg    config.Config
+		scope  string
+		period string
+	}{
+		{"daily", config.Config{AIDailyBudget: 1.5 * testCost}, scopeGlobal, periodDaily},
+		{"monthly", config.Config{AIMonthlyBudget: 1.5 * testCost}, scopeGlobal, periodMonthly},
+		{"key daily", config.Config{AIKeyDailyBudget: 1.5 * testCost}, alice, periodDaily},
+		{"key monthly", config.Config{AIKeyMonthlyBudget: 1.5 * testCost}, alice, periodMonthly},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := newTestUsageTracker(&tc.cfg, &now)
+
+			tracker.Record(alice, "gpt-4o-mini", testUsage)
+			if err := tracker.Allow(alice); err != nil {
+				t.Fatalf("Expected budget left, got %v", err)
+			}
+
+			tracker.Record(alice, "gpt-4o-mini", testUsage)
+			err := tracker.Allow(alice)
+			var budgetErr *BudgetError
+			if !errors.Is(err, ErrBudgetExhausted) || !errors.As(err, &budgetErr) {
+				t.Fatalf("Expected ErrBudgetExhausted, got %v", err)
+			}
+			if budgetErr.Scope != tc.scope || budgetErr.Period != tc.period || !equalCost(budgetErr.Spent, 2*testCost) {
+				t.Errorf("Unexpected error %+v", budgetErr)
+			}
+
+			// Per-key budgets only stop the client that spent them
+			if err := tracker.Allow(bob); (tc.scope == scopeGlobal) != (err != nil) {
+				t.Errorf("Unexpected error for another client: %v", err)
+			}
+		})
+	}
+
+	// Budgets of zero are unlimited
+	tracker := newTestUsageTracker(&config.Config{}, &now)
+	for i := 0; i < 100; i++ {
+		tracker.Record(alice, "gpt-4o", testUsage)
+	}
+	if err := tracker.Allow(alice); err != nil {
+		t.Errorf("Expected no budget, got %v", err)
+	}
+}
+
+func TestUsageTracker_Store(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	st := newMapStore()
+
+	tracker := newTestUsageTracker(&config.Config{}, &now).WithStore(st)
+	tracker.Record(AnonymousClient, "gpt-4o-mini", testUsage)
+
+	if _, err := st.Get("usage/daily/2024-05-01"); err != nil {
+		t.Errorf("Expected the daily usage in the store, got %v", err)
+	}
+
+	// The totals survive restarts
+	restarted := newTestUsageTracker(&config.Config{AIMonthlyBudget: testCost / 2}, &now).WithStore(st)
+	report := restarted.Report()
+	if report.Day.Total.Requests != 1 || report.Month.Clients[AnonymousClient].Requests != 1 {
+		t.Errorf("Expected the stored totals, got %+v", report)
+	}
+	if err := restarted.Allow(AnonymousClient); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Expected the stored spending to count, got %v", err)
+	}
+}
+
+func TestExplain_Budget(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := newTestUsageTracker(&config.Config{AIKeyDailyBudget: testCost / 2}, &now)
+	service := newTestExplanationService(fake, api.URL).WithUsage(usage)
+
+	alice := WithClient(context.Background(), "alice")
+	bob := WithClient(context.Background(), "bob")
+
+	explanation, err := service.Explain(alice, 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if explanation.Degraded || len(fake.requests) != 1 {
+		t.Errorf("Expected an explanation from the model, got %+v", explanation)
+	}
+	if spent := usage.Report().Day.Clients[ClientID("alice")]; spent.PromptTokens != 120 || !equalCost(spent.Cost, testCost) {
+		t.Errorf("Expected the usage of the completion, got %+v", spent)
+	}
+
+	// Past the budget the template provider takes over
+	explanation, err = service.Explain(alice, 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !explanation.Degraded || explanation.Model != templateModel || len(fake.requests) != 1 {
+		t.Errorf("Expected a template explanation, got %+v", explanation)
+	}
+
+	// Other clients keep their budget
+	if explanation, _ := service.Explain(bob, 25, "en", ""); explanation == nil || explanation.Degraded {
+		t.Errorf("Expected an explanation from the model, got %+v", explanation)
+	}
+
+	// Regenerating never degrades
+	if _, err := service.Regenerate(alice, 25, "en", ""); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Expected ErrBudgetExhausted, got %v", err)
+	}
+}
+
+func TestExplain_BudgetServesStored(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := newTestUsageTracker(&config.Config{AIDailyBudget: testCost / 2}, &now)
+	service := newTestExplanationService(fake, api.URL).WithStore(newMapStore()).WithUsage(usage)
+
+	if _, err := service.Explain(context.Background(), 25, "en", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Stored explanations cost nothing and are still served
+	explanation, err := service.Explain(context.Background(), 25, "en", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !explanation.Cached || explanation.Degraded || explanation.Model != "gpt-4o-mini-2024-07-18" {
+		t.Errorf("Expected the stored explanation, got %+v", explanation)
+	}
+	if usage.Report().Day.Total.Requests != 1 {
+		t.Errorf("Expected stored explanations to not count, got %+v", usage.Report().Day.Total)
+	}
+}
+
+func TestExplainStream_RecordsCanceled(t *testing.T) {
+	fake := newFakeOpenAI("Pikachu stores electricity in its cheeks.")
+	defer fake.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := newTestUsageTracker(&config.Config{}, &now)
+	service := newTestExplanationService(fake, api.URL).WithUsage(usage)
+
+	// The client disconnects after the first token
+	ctx, cancel := context.WithCancel(WithClient(context.Background(), "alice"))
+	defer cancel()
+
+	_, err := service.ExplainStream(ctx, 25, "en", "", func(string) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	spent := usage.Report().Day.Clients[ClientID("alice")]
+	if spent.Requests != 1 || spent.PromptTokens == 0 || spent.CompletionTokens != 2 || spent.Cost == 0 {
+		t.Errorf("Expected the partial usage to be recorded, got %+v", spent)
+	}
+}
+
+func TestExplainStream_RecordsFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: {\"model\": \"gpt-4o-mini\", \"choices\": [{\"delta\": {\"content\": \"Pikachu\"}}]}\n\ndata: {\"choices\n\n")
+	}))
+	defer server.Close()
+	api := newFakeAPI()
+	defer api.Close()
+
+	cfg := newOpenAIConfig(server.URL)
+	cfg.APIBaseURL = api.URL
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	usage := newTestUsageTracker(&config.Config{}, &now)
+	service := NewExplanationService(NewPokemonClient(cfg), NewOpenAIProvider(cfg)).WithUsage(usage)
+
+	_, err := service.ExplainStream(context.Background(), 25, "en", "", func(string) error { return nil })
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf("Expected ErrDecode, got %v", err)
+	}
+
+	spent := usage.Report().Day.Total
+	if spent.Requests != 1 || spent.CompletionTokens != 2 || spent.Cost == 0 {
+		t.Errorf("Expected the partial usage to be recorded, got %+v", spent)
+	}
+}
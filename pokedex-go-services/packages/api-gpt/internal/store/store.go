@@ -0,0 +1,85 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when a key is not present in the store
+var ErrNotFound = errors.New("key not found in store")
+
+// explanationsBucket holds the generated explanations keyed by cache key
+var explanationsBucket = []byte("explanations")
+
+// Store represents a persistent key/value store for generated explanations
+// and AI usage totals
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	Close() error
+}
+
+// BoltStore is a Store backed by an embedded bbolt database file
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// Ensure the bolt store satisfies the interface
+var _ Store = (*BoltStore)(nil)
+
+// Open opens (or creates) the database file at the given path
+func Open(path string) (*BoltStore, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating store directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(explanationsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error initializing store: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Get returns a copy of the value stored under the key
+func (s *BoltStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(explanationsBucket).Get([]byte(key))
+		if v == nil {
+			return ErrNotFound
+		}
+		// Values are only valid during the transaction
+		value = append([]byte(nil), v...)
+		return nil
+	})
+	return value, err
+}
+
+// Put stores the value under the key, replacing any previous value
+func (s *BoltStore) Put(key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(explanationsBucket).Put([]byte(key), value)
+	})
+}
+
+// Close closes the database file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBoltStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "explanations.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := s.Get("explanations/25/en"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := s.Put("explanations/25/en", []byte(`{"pokemon_id": 25}`)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	value, err := s.Get("explanations/25/en")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(value) != `{"pokemon_id": 25}` {
+		t.Errorf("Expected stored value, got %s", value)
+	}
+
+	// Values survive reopening the file
+	if err := s.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer s.Close()
+
+	if _, err := s.Get("explanations/25/en"); err != nil {
+		t.Errorf("Expected value after reopening, got %v", err)
+	}
+}
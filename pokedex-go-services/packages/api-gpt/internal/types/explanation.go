@@ -0,0 +1,43 @@
+package types
+
+// ExplanationRequest represents a request for the explanation of a Pokémon
+type ExplanationRequest struct {
+	PokemonID int    `json:"pokemon_id"`
+	Language  string `json:"language"`
+	Persona   string `json:"persona"`
+}
+
+// AIExplanation represents the explanation generated by the AI. Cached is
+// set when it was generated by an earlier request, and Degraded when an AI
+// budget was exhausted and the template provider wrote it instead.
+type AIExplanation struct {
+	PokemonID     int    `json:"pokemon_id"`
+	Name          string `json:"name"`
+	Language      string `json:"language"`
+	Persona       string `json:"persona"`
+	Explanation   string `json:"explanation"`
+	Model         string `json:"model"`
+	PromptVersion string `json:"prompt_version"`
+	GeneratedAt   string `json:"generated_at"`
+	Cached        bool   `json:"cached"`
+	Degraded      bool   `json:"degraded"`
+}
+
+// ExplanationToken represents a piece of an explanation sent while it is generated
+type ExplanationToken struct {
+	Text string `json:"text"`
+}
+
+// Envelope represents the body of every response. Data is set on success
+// and Error on failure.
+type Envelope struct {
+	Success bool       `json:"success"`
+	Data    any        `json:"data,omitempty"`
+	Error   *ErrorBody `json:"error,omitempty"`
+}
+
+// ErrorBody represents a failed request. Code is stable and meant for programs.
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
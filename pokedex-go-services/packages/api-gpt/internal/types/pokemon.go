@@ -0,0 +1,58 @@
+package types
+
+import "encoding/json"
+
+// APIResponse represents the envelope of every response from the Pokémon API
+type APIResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *ErrorBody      `json:"error"`
+}
+
+// PokemonResponse represents a Pokémon as served by the Pokémon API
+type PokemonResponse struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Types         []string `json:"types"`
+	Stats         Stats    `json:"stats"`
+	ImageURL      string   `json:"image_url"`
+	Height        int      `json:"height"`
+	Weight        int      `json:"weight"`
+	Abilities     []string `json:"abilities"`
+	LocalizedName string   `json:"localized_name,omitempty"`
+	Language      string   `json:"language,omitempty"`
+}
+
+// Stats represents the base stats of a Pokémon
+type Stats struct {
+	HP             int `json:"hp"`
+	Attack         int `json:"attack"`
+	Defense        int `json:"defense"`
+	SpecialAttack  int `json:"special_attack"`
+	SpecialDefense int `json:"special_defense"`
+	Speed          int `json:"speed"`
+}
+
+// SpeciesResponse represents the species fields of the Pokémon API used in explanations
+type SpeciesResponse struct {
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Generation  string            `json:"generation"`
+	IsLegendary bool              `json:"is_legendary"`
+	IsMythical  bool              `json:"is_mythical"`
+	EvolvesFrom string            `json:"evolves_from,omitempty"`
+	Localized   *LocalizedSpecies `json:"localized,omitempty"`
+}
+
+// LocalizedSpecies represents the species texts in the requested language
+type LocalizedSpecies struct {
+	Name       string       `json:"name"`
+	Genus      string       `json:"genus"`
+	FlavorText []FlavorText `json:"flavor_text"`
+}
+
+// FlavorText represents a Pokédex entry in one game
+type FlavorText struct {
+	Version string `json:"version"`
+	Text    string `json:"text"`
+}
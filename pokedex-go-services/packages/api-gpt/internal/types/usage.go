@@ -0,0 +1,26 @@
+package types
+
+// UsageTotals represents the AI usage of a client, or of every client, in a period
+type UsageTotals struct {
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// PeriodUsage represents the AI usage of a day or a month against its
+// budgets. A budget of zero is unlimited.
+type PeriodUsage struct {
+	Period    string                 `json:"period"`
+	Budget    float64                `json:"budget"`
+	KeyBudget float64                `json:"key_budget"`
+	Total     UsageTotals            `json:"total"`
+	Clients   map[string]UsageTotals `json:"clients"`
+}
+
+// UsageReport represents the AI usage of the current day and month
+type UsageReport struct {
+	Currency string      `json:"currency"`
+	Day      PeriodUsage `json:"day"`
+	Month    PeriodUsage `json:"month"`
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+	"pokedexia-gpt/internal/config"
+	"pokedexia-gpt/internal/handlers"
+	"pokedexia-gpt/internal/middleware"
+	"pokedexia-gpt/internal/services"
+	"pokedexia-gpt/internal/store"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Println("Environment file not found, using system environment variables")
+	}
+
+	// Initialize the configuration
+	cfg := config.New()
+
+	// Select the LLM provider
+	provider, err := services.NewProvider(cfg)
+	if err != nil {
+		log.Fatal("Error configuring the LLM provider:", err)
+	}
+	if !provider.Configured() {
+		log.Printf("The %s provider is not configured, explanations are unavailable", provider.Name())
+	}
+	log.Printf("Generating explanations with the %s provider (%s)", provider.Name(), provider.Model())
+
+	// Budgets only work when the model has a price
+	if err := services.ValidatePrices(cfg, provider); err != nil {
+		log.Fatal("Error configuring the AI budgets:", err)
+	}
+
+	// Open the store of generated explanations
+	var st store.Store
+	if cfg.CachePath != "" {
+		boltStore, err := store.Open(cfg.CachePath)
+		if err != nil {
+			log.Fatal("Error opening the explanation store:", err)
+		}
+		defer boltStore.Close()
+		st = boltStore
+	}
+
+	// Configure the Gin mode
+	if os.Getenv("GIN_MODE") == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Create the router
+	router := gin.Default()
+
+	// Configure CORS
+	router.Use(func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, Authorization, X-API-Key")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	})
+
+	// Configure the routes
+	setupRoutes(router, cfg, provider, st)
+
+	// Start the server
+	log.Printf("Server started on port %s", cfg.ServerPort)
+	if err := router.Run(":" + cfg.ServerPort); err != nil {
+		log.Fatal("Error starting server:", err)
+	}
+}
+
+// setupRoutes configures all the API routes
+func setupRoutes(router *gin.Engine, cfg *config.Config, provider services.LLMProvider, st store.Store) {
+	// Account AI usage per client
+	router.Use(middleware.IdentifyClient(cfg.APIKeys))
+
+	// Create the services
+	usage := services.NewUsageTracker(cfg).WithStore(st)
+	explanations := services.NewExplanationService(services.NewPokemonClient(cfg), provider).
+		WithStore(st).
+		WithUsage(usage)
+
+	// Create the handlers
+	explanationHandler := handlers.NewExplanationHandler(explanations, provider)
+
+	// API routes group
+	api := router.Group("/api/v1")
+	{
+		api.GET("/health", explanationHandler.HealthCheck)
+		api.POST("/explanations", explanationHandler.CreateExplanation)
+		api.GET("/pokemon/id/:id/explanation/stream", explanationHandler.StreamExplanation)
+
+		// Admin routes, only with a token to protect them
+		if cfg.AdminToken != "" {
+			adminHandler := handlers.NewAdminHandler(explanations, usage)
+
+			admin := api.Group("/admin", handlers.RequireAdminToken(cfg.AdminToken))
+			{
+				admin.POST("/explanations/regenerate", adminHandler.RegenerateExplanation)
+				admin.GET("/ai/usage", adminHandler.GetUsage)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pokedexia-backend/internal/services"
+)
+
+// idsRetryInterval is how long to wait before discovering the valid IDs again after a failure
+const idsRetryInterval = time.Minute
+
+// refreshIDs discovers the valid Pokémon IDs from the PokeAPI and keeps them
+// up to date, refreshing them at every interval and calling onRefresh after
+// each discovery. A zero interval discovers them only once.
+func refreshIDs(service *services.PokeAPIService, interval time.Duration, onRefresh func(*services.PokemonIDs)) {
+	for {
+		wait := interval
+		if err := service.RefreshIDs(context.Background()); err != nil {
+			log.Printf("Error discovering valid Pokémon IDs, retrying in %s: %v", idsRetryInterval, err)
+			wait = idsRetryInterval
+		} else {
+			log.Printf("Valid Pokémon IDs: 1-%d and alternate forms", service.IDs().Max())
+			onRefresh(service.IDs())
+			if interval <= 0 {
+				return
+			}
+		}
+
+		time.Sleep(wait)
+	}
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/store"
+)
+
+// namesRetryInterval is how long to wait before loading the species list again after a failure
+const namesRetryInterval = time.Minute
+
+// loadStore indexes the Pokémon and the localized species names with the
+// valid IDs that are in the local store. Indexing again replaces the entries.
+func loadStore(st store.Store, pokedex *index.Index, names *index.NameIndex, ids *services.PokemonIDs) {
+	loaded, err := pokedex.Load(st, ids.All())
+	if err != nil {
+		log.Printf("Error loading the local index: %v", err)
+	}
+	log.Printf("Indexed %d Pokémon from the local store", loaded)
+
+	loaded, err = names.LoadSpecies(st, ids.Max())
+	if err != nil {
+		log.Printf("Error loading localized names: %v", err)
+	}
+	log.Printf("Indexed localized names of %d species from the local store", loaded)
+}
+
+// loadNames fills the name index with every species name, retrying until
+// the species list can be fetched
+func loadNames(service *services.PokeAPIService, names *index.NameIndex) {
+	for {
+		species, err := service.ListSpecies(context.Background())
+		if err == nil {
+			for _, s := range species {
+				names.Add(services.ResourceID(s.URL), s.Name, i18n.Default)
+			}
+			log.Printf("Indexed %d species names", len(species))
+			return
+		}
+
+		log.Printf("Error loading species names, retrying in %s: %v", namesRetryInterval, err)
+		time.Sleep(namesRetryInterval)
+	}
+}
@@ -4,12 +4,58 @@ import (
 	"github.com/gin-gonic/gin"
 	"pokedexia-backend/internal/config"
 	"pokedexia-backend/internal/handlers"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/middleware"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/store"
+	"pokedexia-backend/internal/types"
 )
 
-// SetupRoutes configures all the API routes
-func SetupRoutes(router *gin.Engine, cfg *config.Config) {
+// SetupRoutes configures all the API routes. The store is optional and may be nil.
+func SetupRoutes(router *gin.Engine, cfg *config.Config, st store.Store) {
+	// Tag every request with an ID and track where its data comes from
+	router.Use(middleware.RequestID(), middleware.TrackOrigin())
+
+	// Create the services
+	pokeAPIService := services.NewPokeAPIService(cfg)
+	if st != nil {
+		pokeAPIService.WithStore(st)
+	}
+
+	var source services.PokemonSource = pokeAPIService
+
+	var cache *services.CachedSource
+	if cfg.CacheSize > 0 {
+		cache = services.NewCachedSource(source, cfg.CacheSize, cfg.CacheTTL)
+		source = cache
+	}
+
+	// Index the Pokémon in the local store for listings, and the species
+	// names for search and autocomplete. Localized names come from the
+	// species in the local store; the English names of every species are
+	// loaded in the background. Pokémon fetched later are indexed as they
+	// are looked up.
+	var pokedex *index.Index
+	names := index.NewNameIndex()
+	if st != nil {
+		pokedex = index.New()
+		loadStore(st, pokedex, names, pokeAPIService.IDs())
+	}
+	go loadNames(pokeAPIService, names)
+
+	// Discover the valid Pokémon IDs, which grow with every new generation,
+	// and index the stored Pokémon with the new IDs
+	go refreshIDs(pokeAPIService, cfg.IDRefreshInterval, func(ids *services.PokemonIDs) {
+		if st != nil {
+			loadStore(st, pokedex, names, ids)
+		}
+	})
+
 	// Create the handlers
-	pokemonHandler := handlers.NewPokemonHandler(cfg)
+	pokemonHandler := handlers.NewPokemonHandler(source).WithNames(names).WithIDs(pokeAPIService.IDs())
+	if pokedex != nil {
+		pokemonHandler.WithIndex(pokedex)
+	}
 
 	// API routes group
 	api := router.Group("/api/v1")
@@ -20,23 +66,52 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config) {
 		// Pokemon routes
 		pokemon := api.Group("/pokemon")
 		{
+			pokemon.GET("", pokemonHandler.ListPokemon)
 			pokemon.GET("/id/:id", pokemonHandler.GetPokemonByID)
+			pokemon.GET("/id/:id/species", pokemonHandler.GetPokemonSpecies)
+			pokemon.GET("/id/:id/evolutions", pokemonHandler.GetPokemonEvolutions)
+			pokemon.GET("/id/:id/moves", pokemonHandler.GetPokemonMoves)
+			pokemon.GET("/id/:id/weaknesses", pokemonHandler.GetPokemonWeaknesses)
 			pokemon.GET("/name/:name", pokemonHandler.GetPokemonByName)
 			pokemon.GET("/search", pokemonHandler.SearchPokemon)
+			pokemon.GET("/autocomplete", pokemonHandler.Autocomplete)
+		}
+
+		// Admin routes, only with a token to protect them
+		if cache != nil && cfg.AdminToken != "" {
+			adminHandler := handlers.NewAdminHandler(cache)
+
+			admin := api.Group("/admin", handlers.RequireAdminToken(cfg.AdminToken))
+			{
+				admin.GET("/cache", adminHandler.GetCacheStats)
+				admin.DELETE("/cache", adminHandler.PurgeCache)
+			}
 		}
 	}
 
 	// Root route
 	router.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
+		data := gin.H{
 			"message": "Welcome to PokedexIA API!",
 			"version": "1.0.0",
 			"endpoints": gin.H{
-				"health": "/api/v1/health",
-				"pokemon_by_id": "/api/v1/pokemon/id/:id",
-				"pokemon_by_name": "/api/v1/pokemon/name/:name",
-				"search_pokemon": "/api/v1/pokemon/search?q=:query",
+				"health":             "/api/v1/health",
+				"list_pokemon":       "/api/v1/pokemon?page=:page&limit=:limit&type=:type&generation=:generation&sort=:field:dir",
+				"pokemon_by_id":      "/api/v1/pokemon/id/:id",
+				"pokemon_species":    "/api/v1/pokemon/id/:id/species",
+				"pokemon_evolutions": "/api/v1/pokemon/id/:id/evolutions",
+				"pokemon_moves":      "/api/v1/pokemon/id/:id/moves?version_group=:version_group&method=:method",
+				"pokemon_weaknesses": "/api/v1/pokemon/id/:id/weaknesses",
+				"pokemon_by_name":    "/api/v1/pokemon/name/:name",
+				"search_pokemon":     "/api/v1/pokemon/search?q=:query",
+				"autocomplete":       "/api/v1/pokemon/autocomplete?q=:prefix",
 			},
+		}
+
+		c.JSON(200, types.Envelope{
+			Success: true,
+			Data:    data,
+			Meta:    types.Meta{RequestID: middleware.GetRequestID(c)},
 		})
 	})
-} 
\ No newline at end of file
+}
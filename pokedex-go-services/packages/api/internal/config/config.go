@@ -2,23 +2,62 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config represents the application configuration
 type Config struct {
-	PokeAPIBaseURL string
-	OpenAIAPIKey   string
-	ServerPort     string
-	Environment    string
+	PokeAPIBaseURL          string
+	OpenAIAPIKey            string
+	ServerPort              string
+	Environment             string
+	CacheSize               int
+	CacheTTL                time.Duration
+	UpstreamTimeout         time.Duration
+	RetryMaxAttempts        int
+	RetryBaseDelay          time.Duration
+	RetryMaxDelay           time.Duration
+	RetryJitter             float64
+	RetryStatusCodes        []int
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+	BreakerHalfOpenRequests int
+	UpstreamRateLimit       float64
+	UpstreamRateBurst       int
+	UpstreamRateMaxWait     time.Duration
+	StorePath               string
+	Offline                 bool
+	IDRefreshInterval       time.Duration
+	AdminToken              string
 }
 
 // New creates a new instance of Config
 func New() *Config {
 	return &Config{
-		PokeAPIBaseURL: getEnv("POKEAPI_BASE_URL", "https://pokeapi.co/api/v2"),
-		OpenAIAPIKey:   getEnv("OPENAI_API_KEY", ""),
-		ServerPort:     getEnv("PORT", "8080"),
-		Environment:    getEnv("ENVIRONMENT", "development"),
+		PokeAPIBaseURL:          getEnv("POKEAPI_BASE_URL", "https://pokeapi.co/api/v2"),
+		OpenAIAPIKey:            getEnv("OPENAI_API_KEY", ""),
+		ServerPort:              getEnv("PORT", "8080"),
+		Environment:             getEnv("ENVIRONMENT", "development"),
+		CacheSize:               getEnvInt("CACHE_SIZE", 1000),
+		CacheTTL:                getEnvDuration("CACHE_TTL", 24*time.Hour),
+		UpstreamTimeout:         getEnvDuration("UPSTREAM_TIMEOUT", 10*time.Second),
+		RetryMaxAttempts:        getEnvInt("RETRY_MAX_ATTEMPTS", 3),
+		RetryBaseDelay:          getEnvDuration("RETRY_BASE_DELAY", 200*time.Millisecond),
+		RetryMaxDelay:           getEnvDuration("RETRY_MAX_DELAY", 5*time.Second),
+		RetryJitter:             getEnvFloat("RETRY_JITTER", 0.2),
+		RetryStatusCodes:        getEnvIntList("RETRY_STATUS_CODES", []int{429, 500, 502, 503, 504}),
+		BreakerFailureThreshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeout:      getEnvDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		BreakerHalfOpenRequests: getEnvInt("BREAKER_HALF_OPEN_REQUESTS", 1),
+		UpstreamRateLimit:       getEnvFloat("UPSTREAM_RATE_LIMIT", 20),
+		UpstreamRateBurst:       getEnvInt("UPSTREAM_RATE_BURST", 10),
+		UpstreamRateMaxWait:     getEnvDuration("UPSTREAM_RATE_MAX_WAIT", 2*time.Second),
+		StorePath:               getEnv("STORE_PATH", ""),
+		Offline:                 getEnvBool("OFFLINE", false),
+		IDRefreshInterval:       getEnvDuration("ID_REFRESH_INTERVAL", 24*time.Hour),
+		AdminToken:              getEnv("ADMIN_TOKEN", ""),
 	}
 }
 
@@ -28,4 +67,55 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-} 
\ No newline at end of file
+}
+
+// getEnvInt returns the environment variable parsed as an integer or the default value
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvFloat returns the environment variable parsed as a float or the default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvIntList returns the environment variable parsed as a comma-separated
+// list of integers, or the default value if it is unset or invalid
+func getEnvIntList(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return defaultValue
+		}
+		list = append(list, n)
+	}
+	return list
+}
+
+// getEnvBool returns the environment variable parsed as a boolean or the default value
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvDuration returns the environment variable parsed as a duration (e.g. "30s", "24h") or the default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +14,19 @@ func TestNew(t *testing.T) {
 	os.Unsetenv("OPENAI_API_KEY")
 	os.Unsetenv("PORT")
 	os.Unsetenv("ENVIRONMENT")
+	os.Unsetenv("CACHE_SIZE")
+	os.Unsetenv("CACHE_TTL")
+	os.Unsetenv("UPSTREAM_TIMEOUT")
+	os.Unsetenv("RETRY_MAX_ATTEMPTS")
+	os.Unsetenv("RETRY_STATUS_CODES")
+	os.Unsetenv("BREAKER_FAILURE_THRESHOLD")
+	os.Unsetenv("BREAKER_OPEN_TIMEOUT")
+	os.Unsetenv("UPSTREAM_RATE_LIMIT")
+	os.Unsetenv("UPSTREAM_RATE_MAX_WAIT")
+	os.Unsetenv("STORE_PATH")
+	os.Unsetenv("OFFLINE")
+	os.Unsetenv("ID_REFRESH_INTERVAL")
+	os.Unsetenv("ADMIN_TOKEN")
 
 	cfg := New()
 
@@ -21,6 +35,24 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, "", cfg.OpenAIAPIKey)
 	assert.Equal(t, "8080", cfg.ServerPort)
 	assert.Equal(t, "development", cfg.Environment)
+	assert.Equal(t, 1000, cfg.CacheSize)
+	assert.Equal(t, 24*time.Hour, cfg.CacheTTL)
+	assert.Equal(t, 10*time.Second, cfg.UpstreamTimeout)
+	assert.Equal(t, 3, cfg.RetryMaxAttempts)
+	assert.Equal(t, 200*time.Millisecond, cfg.RetryBaseDelay)
+	assert.Equal(t, 5*time.Second, cfg.RetryMaxDelay)
+	assert.Equal(t, 0.2, cfg.RetryJitter)
+	assert.Equal(t, []int{429, 500, 502, 503, 504}, cfg.RetryStatusCodes)
+	assert.Equal(t, 5, cfg.BreakerFailureThreshold)
+	assert.Equal(t, 30*time.Second, cfg.BreakerOpenTimeout)
+	assert.Equal(t, 1, cfg.BreakerHalfOpenRequests)
+	assert.Equal(t, 20.0, cfg.UpstreamRateLimit)
+	assert.Equal(t, 10, cfg.UpstreamRateBurst)
+	assert.Equal(t, 2*time.Second, cfg.UpstreamRateMaxWait)
+	assert.Equal(t, "", cfg.StorePath)
+	assert.False(t, cfg.Offline)
+	assert.Equal(t, 24*time.Hour, cfg.IDRefreshInterval)
+	assert.Equal(t, "", cfg.AdminToken)
 }
 
 func TestNew_WithEnvironmentVariables(t *testing.T) {
@@ -29,6 +61,19 @@ func TestNew_WithEnvironmentVariables(t *testing.T) {
 	os.Setenv("OPENAI_API_KEY", "test-key")
 	os.Setenv("PORT", "3000")
 	os.Setenv("ENVIRONMENT", "production")
+	os.Setenv("CACHE_SIZE", "50")
+	os.Setenv("CACHE_TTL", "10m")
+	os.Setenv("UPSTREAM_TIMEOUT", "3s")
+	os.Setenv("RETRY_MAX_ATTEMPTS", "5")
+	os.Setenv("RETRY_STATUS_CODES", "502, 503")
+	os.Setenv("BREAKER_FAILURE_THRESHOLD", "10")
+	os.Setenv("BREAKER_OPEN_TIMEOUT", "1m")
+	os.Setenv("UPSTREAM_RATE_LIMIT", "2.5")
+	os.Setenv("UPSTREAM_RATE_MAX_WAIT", "500ms")
+	os.Setenv("STORE_PATH", "data/pokeapi.db")
+	os.Setenv("OFFLINE", "true")
+	os.Setenv("ID_REFRESH_INTERVAL", "6h")
+	os.Setenv("ADMIN_TOKEN", "admin-token")
 
 	cfg := New()
 
@@ -37,12 +82,38 @@ func TestNew_WithEnvironmentVariables(t *testing.T) {
 	assert.Equal(t, "test-key", cfg.OpenAIAPIKey)
 	assert.Equal(t, "3000", cfg.ServerPort)
 	assert.Equal(t, "production", cfg.Environment)
+	assert.Equal(t, 50, cfg.CacheSize)
+	assert.Equal(t, 10*time.Minute, cfg.CacheTTL)
+	assert.Equal(t, 3*time.Second, cfg.UpstreamTimeout)
+	assert.Equal(t, 5, cfg.RetryMaxAttempts)
+	assert.Equal(t, []int{502, 503}, cfg.RetryStatusCodes)
+	assert.Equal(t, 10, cfg.BreakerFailureThreshold)
+	assert.Equal(t, time.Minute, cfg.BreakerOpenTimeout)
+	assert.Equal(t, 2.5, cfg.UpstreamRateLimit)
+	assert.Equal(t, 500*time.Millisecond, cfg.UpstreamRateMaxWait)
+	assert.Equal(t, "data/pokeapi.db", cfg.StorePath)
+	assert.True(t, cfg.Offline)
+	assert.Equal(t, 6*time.Hour, cfg.IDRefreshInterval)
+	assert.Equal(t, "admin-token", cfg.AdminToken)
 
 	// Clean up
 	os.Unsetenv("POKEAPI_BASE_URL")
 	os.Unsetenv("OPENAI_API_KEY")
 	os.Unsetenv("PORT")
 	os.Unsetenv("ENVIRONMENT")
+	os.Unsetenv("CACHE_SIZE")
+	os.Unsetenv("CACHE_TTL")
+	os.Unsetenv("UPSTREAM_TIMEOUT")
+	os.Unsetenv("RETRY_MAX_ATTEMPTS")
+	os.Unsetenv("RETRY_STATUS_CODES")
+	os.Unsetenv("BREAKER_FAILURE_THRESHOLD")
+	os.Unsetenv("BREAKER_OPEN_TIMEOUT")
+	os.Unsetenv("UPSTREAM_RATE_LIMIT")
+	os.Unsetenv("UPSTREAM_RATE_MAX_WAIT")
+	os.Unsetenv("STORE_PATH")
+	os.Unsetenv("OFFLINE")
+	os.Unsetenv("ID_REFRESH_INTERVAL")
+	os.Unsetenv("ADMIN_TOKEN")
 }
 
 func TestGetEnv(t *testing.T) {
@@ -60,4 +131,64 @@ func TestGetEnv(t *testing.T) {
 	os.Setenv("EMPTY_VAR", "")
 	value = getEnv("EMPTY_VAR", "default_value")
 	assert.Equal(t, "default_value", value)
-} 
\ No newline at end of file
+}
+
+func TestGetEnvInt(t *testing.T) {
+	os.Setenv("TEST_INT", "42")
+	assert.Equal(t, 42, getEnvInt("TEST_INT", 7))
+
+	// Invalid values fall back to the default
+	os.Setenv("TEST_INT", "not-a-number")
+	assert.Equal(t, 7, getEnvInt("TEST_INT", 7))
+
+	os.Unsetenv("TEST_INT")
+	assert.Equal(t, 7, getEnvInt("TEST_INT", 7))
+}
+
+func TestGetEnvFloat(t *testing.T) {
+	os.Setenv("TEST_FLOAT", "0.5")
+	assert.Equal(t, 0.5, getEnvFloat("TEST_FLOAT", 1))
+
+	// Invalid values fall back to the default
+	os.Setenv("TEST_FLOAT", "half")
+	assert.Equal(t, 1.0, getEnvFloat("TEST_FLOAT", 1))
+
+	os.Unsetenv("TEST_FLOAT")
+	assert.Equal(t, 1.0, getEnvFloat("TEST_FLOAT", 1))
+}
+
+func TestGetEnvIntList(t *testing.T) {
+	os.Setenv("TEST_LIST", "500, 502,503")
+	assert.Equal(t, []int{500, 502, 503}, getEnvIntList("TEST_LIST", []int{1}))
+
+	// Invalid values fall back to the default
+	os.Setenv("TEST_LIST", "500,oops")
+	assert.Equal(t, []int{1}, getEnvIntList("TEST_LIST", []int{1}))
+
+	os.Unsetenv("TEST_LIST")
+	assert.Equal(t, []int{1}, getEnvIntList("TEST_LIST", []int{1}))
+}
+
+func TestGetEnvBool(t *testing.T) {
+	os.Setenv("TEST_BOOL", "true")
+	assert.True(t, getEnvBool("TEST_BOOL", false))
+
+	// Invalid values fall back to the default
+	os.Setenv("TEST_BOOL", "maybe")
+	assert.False(t, getEnvBool("TEST_BOOL", false))
+
+	os.Unsetenv("TEST_BOOL")
+	assert.True(t, getEnvBool("TEST_BOOL", true))
+}
+
+func TestGetEnvDuration(t *testing.T) {
+	os.Setenv("TEST_DURATION", "90s")
+	assert.Equal(t, 90*time.Second, getEnvDuration("TEST_DURATION", time.Minute))
+
+	// Invalid values fall back to the default
+	os.Setenv("TEST_DURATION", "ninety")
+	assert.Equal(t, time.Minute, getEnvDuration("TEST_DURATION", time.Minute))
+
+	os.Unsetenv("TEST_DURATION")
+	assert.Equal(t, time.Minute, getEnvDuration("TEST_DURATION", time.Minute))
+}
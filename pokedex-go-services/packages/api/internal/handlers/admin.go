@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/services"
+)
+
+// AdminHandler represents the handler for administrative endpoints
+type AdminHandler struct {
+	cache services.CacheManager
+}
+
+// NewAdminHandler creates a new instance of the handler
+func NewAdminHandler(cache services.CacheManager) *AdminHandler {
+	return &AdminHandler{
+		cache: cache,
+	}
+}
+
+// GetCacheStats returns the cache counters
+func (h *AdminHandler) GetCacheStats(c *gin.Context) {
+	respondData(c, h.cache.Stats())
+}
+
+// PurgeCache removes every entry from the cache
+func (h *AdminHandler) PurgeCache(c *gin.Context) {
+	removed := h.cache.Purge()
+
+	respondData(c, gin.H{
+		"removed": removed,
+	})
+}
+
+// RequireAdminToken rejects the requests that do not send the admin token
+// as a bearer token in the Authorization header
+func RequireAdminToken(token string) gin.HandlerFunc {
+	expected := []byte("Bearer " + token)
+
+	return func(c *gin.Context) {
+		authorization := []byte(strings.TrimSpace(c.GetHeader("Authorization")))
+		if token == "" || subtle.ConstantTimeCompare(authorization, expected) != 1 {
+			respondError(c, http.StatusUnauthorized, i18n.CodeUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
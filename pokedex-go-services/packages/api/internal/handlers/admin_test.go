@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func TestAdminCache(t *testing.T) {
+	router := setupTestRouter()
+	cache := services.NewCachedSource(services.NewMemorySource(newPikachu()), 10, time.Hour)
+	handler := NewAdminHandler(cache)
+
+	router.GET("/admin/cache", handler.GetCacheStats)
+	router.DELETE("/admin/cache", handler.PurgeCache)
+
+	cache.GetPokemonByID(context.Background(), 25)
+	cache.GetPokemonByID(context.Background(), 25)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/cache", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stats struct {
+		Data struct {
+			Entries int `json:"entries"`
+			Hits    int `json:"hits"`
+			Misses  int `json:"misses"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, 1, stats.Data.Entries)
+	assert.Equal(t, 1, stats.Data.Hits)
+	assert.Equal(t, 1, stats.Data.Misses)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/admin/cache", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var purge struct {
+		Data struct {
+			Removed int `json:"removed"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &purge))
+	assert.Equal(t, 1, purge.Data.Removed)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
+
+func TestRequireAdminToken(t *testing.T) {
+	router := setupTestRouter()
+	cache := services.NewCachedSource(services.NewMemorySource(newPikachu()), 10, time.Hour)
+	handler := NewAdminHandler(cache)
+
+	router.DELETE("/admin/cache", RequireAdminToken("admin-token"), handler.PurgeCache)
+
+	cache.GetPokemonByID(context.Background(), 25)
+
+	for _, authorization := range []string{"", "admin-token", "Bearer other-token"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/admin/cache", nil)
+		req.Header.Set("Authorization", authorization)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, authorization)
+
+		var response types.Envelope
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "UNAUTHORIZED", response.Error.Code)
+	}
+	assert.Equal(t, 1, cache.Stats().Entries)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/admin/cache", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, cache.Stats().Entries)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/middleware"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+// problemTypePrefix identifies the problem types of the API; the error code follows it
+const problemTypePrefix = "urn:pokedexia:problem:"
+
+// statusClientClosedRequest is used when the client disconnects before the response is ready
+const statusClientClosedRequest = 499
+
+// respondServiceError maps an error from the Pokémon source to the matching HTTP response
+func respondServiceError(c *gin.Context, err error) {
+	// Nobody is waiting for the response anymore
+	if errors.Is(err, context.Canceled) {
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		respondError(c, http.StatusGatewayTimeout, i18n.CodeUpstreamTimeout)
+	case errors.Is(err, services.ErrMoveNotFound):
+		// The Pokémon exists, but the PokeAPI lacks a move it refers to
+		respondError(c, http.StatusBadGateway, i18n.CodeMoveNotFound)
+	case errors.Is(err, services.ErrNotFound):
+		respondError(c, http.StatusNotFound, i18n.CodePokemonNotFound)
+	case errors.Is(err, services.ErrRateLimited):
+		var upstreamErr *services.UpstreamError
+		if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(upstreamErr.RetryAfter.Seconds())))
+		}
+		respondError(c, http.StatusTooManyRequests, i18n.CodeUpstreamRateLimited)
+	case errors.Is(err, services.ErrUpstreamUnavailable):
+		respondError(c, http.StatusServiceUnavailable, i18n.CodeUpstreamUnavailable)
+	case errors.Is(err, services.ErrDecode):
+		respondError(c, http.StatusBadGateway, i18n.CodeUpstreamInvalidResponse)
+	default:
+		// The error may hold internal details, such as upstream URLs, so it is only logged
+		log.Printf("Unexpected error on %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		respondError(c, http.StatusInternalServerError, i18n.CodeInternalError)
+	}
+}
+
+// respondError writes an error response with a stable code and the message
+// of that code in the client's language
+func respondError(c *gin.Context, status int, code i18n.Code, args ...any) {
+	respondErrorDetails(c, status, code, nil, args...)
+}
+
+// respondErrorDetails writes an error response with extra details for the client
+func respondErrorDetails(c *gin.Context, status int, code i18n.Code, details gin.H, args ...any) {
+	writeError(c, status, types.ErrorBody{
+		Code:    string(code),
+		Message: i18n.Message(messageLanguage(c), code, args...),
+		Details: details,
+	})
+}
+
+// respondInvalidParam writes a 400 response for a request parameter that failed validation
+func respondInvalidParam(c *gin.Context, param string, code i18n.Code, args ...any) {
+	message := i18n.Message(messageLanguage(c), code, args...)
+
+	writeError(c, http.StatusBadRequest, types.ErrorBody{
+		Code:          string(code),
+		Message:       message,
+		InvalidParams: []types.InvalidParam{{Name: param, Reason: message}},
+	})
+}
+
+// writeError writes the error as an RFC 7807 problem when the client prefers
+// it in the Accept header, or in the response envelope otherwise
+func writeError(c *gin.Context, status int, body types.ErrorBody) {
+	if c.NegotiateFormat(gin.MIMEJSON, types.ProblemContentType) != types.ProblemContentType {
+		respond(c, status, types.Envelope{Error: &body})
+		return
+	}
+
+	problem := types.Problem{
+		Type:          problemTypePrefix + strings.ToLower(strings.ReplaceAll(body.Code, "_", "-")),
+		Title:         i18n.Title(i18n.Code(body.Code)),
+		Status:        status,
+		Detail:        body.Message,
+		Instance:      c.Request.URL.RequestURI(),
+		Code:          body.Code,
+		RequestID:     middleware.GetRequestID(c),
+		InvalidParams: body.InvalidParams,
+		Extensions:    body.Details,
+	}
+
+	// The JSON renderer keeps a Content-Type that is already set
+	c.Header("Content-Type", types.ProblemContentType)
+	c.JSON(status, problem)
+}
+
+// messageLanguage returns the language of the messages for the client: the
+// lang parameter if it is supported, otherwise the Accept-Language header
+func messageLanguage(c *gin.Context) string {
+	if code, ok := i18n.Parse(c.Query("lang")); ok {
+		return code
+	}
+	return i18n.NegotiateMessages(c.GetHeader("Accept-Language"))
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/middleware"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func TestProblemResponse_InvalidParams(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/abc?lang=en", nil)
+	req.Header.Set("Accept", "application/problem+json")
+	req.Header.Set(middleware.RequestIDHeader, "req-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, types.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem types.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+
+	message := i18n.Message("en", i18n.CodeInvalidID, services.MaxPokemonID)
+	assert.Equal(t, "urn:pokedexia:problem:invalid-id", problem.Type)
+	assert.Equal(t, i18n.Title(i18n.CodeInvalidID), problem.Title)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, message, problem.Detail)
+	assert.Equal(t, "/pokemon/id/abc?lang=en", problem.Instance)
+	assert.Equal(t, string(i18n.CodeInvalidID), problem.Code)
+	assert.Equal(t, "req-1", problem.RequestID)
+	assert.Equal(t, []types.InvalidParam{{Name: "id", Reason: message}}, problem.InvalidParams)
+}
+
+func TestProblemResponse_Extensions(t *testing.T) {
+	router := setupTestRouter()
+	handler := newNamesTestHandler()
+
+	router.GET("/search", handler.SearchPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=pikach", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json;q=0.5")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, types.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem struct {
+		Status      int                 `json:"status"`
+		Code        string              `json:"code"`
+		Suggestions []types.PokemonName `json:"suggestions"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, string(i18n.CodePokemonNotFound), problem.Code)
+	assert.NotEmpty(t, problem.Suggestions)
+}
+
+func TestProblemResponse_Negotiation(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	testCases := []struct {
+		accept  string
+		problem bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/json, application/problem+json", false},
+		{"application/problem+json", true},
+		{"application/problem+json, */*", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.accept, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/pokemon/id/0", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			if tc.problem {
+				assert.Equal(t, types.ProblemContentType, w.Header().Get("Content-Type"))
+				return
+			}
+
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			body := decodeError(t, w)
+			assert.Equal(t, string(i18n.CodeInvalidID), body.Code)
+			assert.Equal(t, []types.InvalidParam{{Name: "id", Reason: body.Message}}, body.InvalidParams)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+// requestLanguage returns the PokeAPI language the client asked for with the
+// lang parameter or the Accept-Language header, or an empty string if it did
+// not ask for one. It writes a 400 response and returns false when the lang
+// parameter is not supported.
+func requestLanguage(c *gin.Context) (string, bool) {
+	if lang := c.Query("lang"); lang != "" {
+		code, ok := i18n.Parse(lang)
+		if !ok {
+			respondInvalidParam(c, "lang", i18n.CodeUnsupportedLanguage, lang)
+			return "", false
+		}
+		return code, true
+	}
+
+	return i18n.Negotiate(c.GetHeader("Accept-Language")), true
+}
+
+// speciesOf searches for the species of a Pokémon. Forms such as Alolan
+// variants point to their base species.
+func (h *PokemonHandler) speciesOf(ctx context.Context, pokemon *types.Pokemon) (*types.PokemonSpecies, error) {
+	speciesID := services.ResourceID(pokemon.Species.URL)
+	if speciesID == 0 {
+		speciesID = pokemon.ID
+	}
+
+	species, err := h.source.GetSpecies(ctx, speciesID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Learn the localized names so they can be searched
+	if h.names != nil {
+		h.names.AddSpecies(species)
+	}
+	return species, nil
+}
+
+// localize sets the name of the Pokémon in the requested language. The
+// English name is kept when the species cannot be fetched.
+func (h *PokemonHandler) localize(c *gin.Context, pokemon *types.Pokemon, response *types.PokemonResponse, lang string) {
+	if lang == "" {
+		return
+	}
+
+	response.Language = lang
+	response.LocalizedName = pokemon.Name
+
+	species, err := h.speciesOf(c.Request.Context(), pokemon)
+	if err != nil {
+		if !errors.Is(err, services.ErrNotFound) {
+			log.Printf("Error fetching the species of %s for its localized name: %v", pokemon.Name, err)
+		}
+		return
+	}
+
+	names := make(map[string]string, len(species.Names))
+	for _, name := range species.Names {
+		names[name.Language.Name] = name.Name
+	}
+	if name, ok := i18n.Select(names, lang); ok {
+		response.LocalizedName = name
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func localizedName(name, language string) types.LocalizedName {
+	return types.LocalizedName{Name: name, Language: types.NamedResource{Name: language}}
+}
+
+func newLanguageTestHandler() (*PokemonHandler, *index.NameIndex) {
+	pikachu := newPikachu()
+	pikachu.Species = types.NamedResource{Name: "pikachu", URL: "https://pokeapi.co/api/v2/pokemon-species/25/"}
+
+	source := services.NewMemorySource(pikachu)
+	source.AddSpecies(&types.PokemonSpecies{
+		ID:   25,
+		Name: "pikachu",
+		Names: []types.LocalizedName{
+			localizedName("Pikachu", "en"),
+			localizedName("ピカチュウ", "ja-Hrkt"),
+			localizedName("皮卡丘", "zh-Hans"),
+		},
+		Genera: []types.Genus{
+			{Genus: "Mouse Pokémon", Language: types.NamedResource{Name: "en"}},
+			{Genus: "ねずみポケモン", Language: types.NamedResource{Name: "ja-Hrkt"}},
+		},
+	})
+
+	names := index.NewNameIndex()
+	return NewPokemonHandler(source).WithNames(names), names
+}
+
+func TestGetPokemonByID_Language(t *testing.T) {
+	router := setupTestRouter()
+	handler, _ := newLanguageTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		expected string
+		language string
+	}{
+		{"lang parameter", "/pokemon/id/25?lang=ja", "", "ピカチュウ", "ja"},
+		{"Accept-Language header", "/pokemon/id/25", "zh-CN,zh;q=0.9", "皮卡丘", "zh-Hans"},
+		{"parameter wins over header", "/pokemon/id/25?lang=zh-Hans", "ja", "皮卡丘", "zh-Hans"},
+		// PokeAPI has no Portuguese names yet
+		{"Portuguese falls back to English", "/pokemon/id/25", "pt-BR", "Pikachu", "pt-BR"},
+		{"no language", "/pokemon/id/25", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set("Accept-Language", tt.header)
+		}
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, tt.name)
+		data := decodeData(t, w)
+		assert.Equal(t, "pikachu", data.Name, tt.name)
+		assert.Equal(t, tt.expected, data.LocalizedName, tt.name)
+		assert.Equal(t, tt.language, data.Language, tt.name)
+	}
+}
+
+func TestGetPokemonByID_UnsupportedLanguage(t *testing.T) {
+	router := setupTestRouter()
+	handler, _ := newLanguageTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25?lang=xx", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Unsupported Accept-Language values are ignored instead
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/pokemon/id/25", nil)
+	req.Header.Set("Accept-Language", "xx")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, decodeData(t, w).LocalizedName)
+}
+
+func TestGetPokemonSpecies_Language(t *testing.T) {
+	router := setupTestRouter()
+	handler, _ := newLanguageTestHandler()
+
+	router.GET("/pokemon/id/:id/species", handler.GetPokemonSpecies)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25/species?lang=ja", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data types.SpeciesResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "ja", response.Data.Language)
+	assert.Equal(t, "ピカチュウ", response.Data.Localized.Name)
+	assert.Equal(t, "ねずみポケモン", response.Data.Localized.Genus)
+	assert.Equal(t, "皮卡丘", response.Data.Names["zh-Hans"])
+}
+
+func TestSearchPokemon_LocalizedName(t *testing.T) {
+	router := setupTestRouter()
+	handler, names := newLanguageTestHandler()
+
+	router.GET("/search", handler.SearchPokemon)
+
+	// Localized names become searchable once the species is known
+	species, _ := handler.source.GetSpecies(context.Background(), 25)
+	names.AddSpecies(species)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=ピカチュウ", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 25, decodeData(t, w).ID)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// WithIndex sets the local index used by the listing endpoint. Pokémon
+// looked up through the handler are added to it.
+func (h *PokemonHandler) WithIndex(ix *index.Index) *PokemonHandler {
+	h.index = ix
+	return h
+}
+
+// indexPokemon adds a Pokémon that is not indexed yet to the local index,
+// with the generation of its species. The response must not be localized.
+func (h *PokemonHandler) indexPokemon(ctx context.Context, pokemon *types.Pokemon, response *types.PokemonResponse) {
+	if h.index == nil || h.index.Has(pokemon.ID) {
+		return
+	}
+
+	entry := index.Entry{Pokemon: *response}
+
+	species, err := h.speciesOf(ctx, pokemon)
+	switch {
+	case err == nil:
+		entry.Generation, _ = index.ParseGeneration(species.Generation.Name)
+	case !errors.Is(err, services.ErrNotFound):
+		// Indexed on a later lookup, once its generation is known
+		if !errors.Is(err, context.Canceled) {
+			log.Printf("Error fetching the species of %s for the index: %v", pokemon.Name, err)
+		}
+		return
+	}
+
+	h.index.Add(entry)
+}
+
+// ListPokemon returns a page of Pokémon summaries from the local index,
+// optionally filtered by type and generation and sorted by a stat
+func (h *PokemonHandler) ListPokemon(c *gin.Context) {
+	if h.index == nil {
+		respondError(c, http.StatusServiceUnavailable, i18n.CodeIndexUnavailable)
+		return
+	}
+
+	page, err := queryInt(c, "page", 1)
+	if err != nil || page < 1 {
+		respondInvalidParam(c, "page", i18n.CodeInvalidPage)
+		return
+	}
+
+	limit, err := queryInt(c, "limit", defaultPageLimit)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		respondInvalidParam(c, "limit", i18n.CodeInvalidLimit, maxPageLimit)
+		return
+	}
+
+	// The offset of the page must not overflow
+	if page > (math.MaxInt-1)/limit {
+		respondInvalidParam(c, "page", i18n.CodeInvalidPage)
+		return
+	}
+
+	query := index.Query{
+		Type:   strings.ToLower(c.Query("type")),
+		Offset: (page - 1) * limit,
+		Limit:  limit,
+	}
+
+	if generation := c.Query("generation"); generation != "" {
+		if query.Generation, err = index.ParseGeneration(generation); err != nil {
+			respondInvalidParam(c, "generation", i18n.CodeInvalidGeneration, generation)
+			return
+		}
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		field, direction, _ := strings.Cut(strings.ToLower(sort), ":")
+		if direction != "" && direction != "asc" && direction != "desc" {
+			respondInvalidParam(c, "sort", i18n.CodeInvalidSort, sort)
+			return
+		}
+		query.SortField = field
+		query.Descending = direction == "desc"
+	}
+
+	result, err := h.index.Query(query)
+	switch {
+	case errors.Is(err, index.ErrUnknownType):
+		respondInvalidParam(c, "type", i18n.CodeInvalidType, query.Type)
+		return
+	case errors.Is(err, index.ErrUnknownSortField):
+		respondInvalidParam(c, "sort", i18n.CodeInvalidSort, c.Query("sort"))
+		return
+	case err != nil:
+		respondServiceError(c, err)
+		return
+	}
+
+	pagination := types.Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      result.Total,
+		TotalPages: (result.Total + limit - 1) / limit,
+	}
+	if page < pagination.TotalPages {
+		pagination.Next = pageURL(c, page+1)
+	}
+	if page > 1 {
+		pagination.Prev = pageURL(c, min(page-1, max(pagination.TotalPages, 1)))
+	}
+
+	respondPage(c, result.Pokemon, pagination)
+}
+
+// queryInt parses an optional integer query parameter
+func queryInt(c *gin.Context, key string, fallback int) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// pageURL returns the URL of the current request pointing at another page
+func pageURL(c *gin.Context, page int) *string {
+	values := c.Request.URL.Query()
+	values.Set("page", strconv.Itoa(page))
+
+	u := c.Request.URL.Path + "?" + values.Encode()
+	return &u
+}
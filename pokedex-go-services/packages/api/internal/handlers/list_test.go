@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+type listResponse struct {
+	Success bool                    `json:"success"`
+	Data    []types.PokemonResponse `json:"data"`
+	Meta    struct {
+		Pagination types.Pagination `json:"pagination"`
+	} `json:"meta"`
+}
+
+func newListTestRouter() *gin.Engine {
+	ix := index.New()
+	for id := 1; id <= 9; id++ {
+		pokemonType := "grass"
+		if id > 3 {
+			pokemonType = "fire"
+		}
+		ix.Add(index.Entry{
+			Pokemon: types.PokemonResponse{
+				ID:    id,
+				Types: []string{pokemonType},
+				Stats: types.Stats{Attack: id * 10},
+			},
+			Generation: 1,
+		})
+	}
+
+	router := setupTestRouter()
+	handler := newTestHandler().WithIndex(ix)
+	router.GET("/pokemon", handler.ListPokemon)
+	return router
+}
+
+func TestListPokemon(t *testing.T) {
+	router := newListTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon?type=fire&sort=attack:desc&limit=2&page=2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response listResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, 7, response.Data[0].ID)
+	assert.Equal(t, 6, response.Data[1].ID)
+
+	assert.Equal(t, 6, response.Meta.Pagination.Total)
+	assert.Equal(t, 3, response.Meta.Pagination.TotalPages)
+	assert.Equal(t, "/pokemon?limit=2&page=3&sort=attack%3Adesc&type=fire", *response.Meta.Pagination.Next)
+	assert.Equal(t, "/pokemon?limit=2&page=1&sort=attack%3Adesc&type=fire", *response.Meta.Pagination.Prev)
+}
+
+func TestListPokemon_Defaults(t *testing.T) {
+	router := newListTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response listResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Data, 9)
+	assert.Equal(t, 1, response.Data[0].ID)
+	assert.Equal(t, 1, response.Meta.Pagination.Page)
+	assert.Equal(t, 20, response.Meta.Pagination.Limit)
+	assert.Nil(t, response.Meta.Pagination.Next)
+	assert.Nil(t, response.Meta.Pagination.Prev)
+}
+
+func TestListPokemon_InvalidParameters(t *testing.T) {
+	router := newListTestRouter()
+
+	for _, query := range []string{
+		"page=0",
+		"page=abc",
+		"limit=0",
+		"limit=101",
+		"generation=xi",
+		"sort=attack:sideways",
+		"sort=cuteness",
+		"type=sound",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/pokemon?"+query, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestListPokemon_HugePage(t *testing.T) {
+	router := newListTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon?page=9223372036854775807&limit=20", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response types.Envelope
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "INVALID_PAGE", response.Error.Code)
+	assert.Equal(t, "page", response.Error.InvalidParams[0].Name)
+}
+
+func TestListPokemon_WithoutIndex(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/pokemon", newTestHandler().ListPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, string(i18n.CodeIndexUnavailable), decodeError(t, w).Code)
+}
+
+func TestListPokemon_IndexesFetchedPokemon(t *testing.T) {
+	source := services.NewMemorySource(newPikachu())
+	source.AddSpecies(&types.PokemonSpecies{ID: 25, Name: "pikachu", Generation: types.NamedResource{Name: "generation-i"}})
+
+	router := setupTestRouter()
+	handler := NewPokemonHandler(source).WithIndex(index.New())
+	router.GET("/pokemon", handler.ListPokemon)
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	list := func() listResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/pokemon?generation=1", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response listResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Empty(t, list().Data)
+
+	// Looking up a Pokémon indexes it with its generation, in English
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25?lang=es", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	response := list()
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, "pikachu", response.Data[0].Name)
+		assert.Empty(t, response.Data[0].Language)
+	}
+}
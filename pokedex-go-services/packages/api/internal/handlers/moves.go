@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/services"
+)
+
+// GetPokemonMoves returns the moves a Pokémon learns in a version group,
+// optionally filtered by learn method
+func (h *PokemonHandler) GetPokemonMoves(c *gin.Context) {
+	id, ok := h.validateID(c, "id", c.Param("id"))
+	if !ok {
+		return
+	}
+
+	versionGroup := c.Query("version_group")
+	if versionGroup == "" {
+		respondInvalidParam(c, "version_group", i18n.CodeVersionGroupRequired)
+		return
+	}
+
+	pokemon, err := h.source.GetPokemonByID(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	learnset, err := services.Learnset(c.Request.Context(), h.source, pokemon, versionGroup, c.Query("method"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondData(c, learnset)
+}
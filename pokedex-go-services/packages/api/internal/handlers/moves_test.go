@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func newMovesTestHandler() *PokemonHandler {
+	pikachu := newPikachu()
+	pikachu.Moves = []types.PokemonMove{
+		{
+			Move: types.NamedResource{Name: "thunder", URL: "https://pokeapi.co/api/v2/move/87/"},
+			VersionGroupDetails: []types.MoveVersionDetail{
+				{LevelLearnedAt: 32, MoveLearnMethod: types.NamedResource{Name: "level-up"}, VersionGroup: types.NamedResource{Name: "scarlet-violet"}},
+			},
+		},
+		{
+			Move: types.NamedResource{Name: "thunder-shock", URL: "https://pokeapi.co/api/v2/move/84/"},
+			VersionGroupDetails: []types.MoveVersionDetail{
+				{LevelLearnedAt: 1, MoveLearnMethod: types.NamedResource{Name: "level-up"}, VersionGroup: types.NamedResource{Name: "scarlet-violet"}},
+			},
+		},
+		{
+			// Missing from the source
+			Move: types.NamedResource{Name: "thunder-punch", URL: "https://pokeapi.co/api/v2/move/9/"},
+			VersionGroupDetails: []types.MoveVersionDetail{
+				{LevelLearnedAt: 0, MoveLearnMethod: types.NamedResource{Name: "tutor"}, VersionGroup: types.NamedResource{Name: "red-blue"}},
+			},
+		},
+	}
+
+	source := services.NewMemorySource(pikachu)
+	source.AddMove(&types.Move{ID: 84, Name: "thunder-shock", Power: intPtr(40), Type: types.NamedResource{Name: "electric"}})
+	source.AddMove(&types.Move{ID: 87, Name: "thunder", Power: intPtr(110), Type: types.NamedResource{Name: "electric"}})
+	return NewPokemonHandler(source)
+}
+
+func TestGetPokemonMoves(t *testing.T) {
+	router := setupTestRouter()
+	handler := newMovesTestHandler()
+
+	router.GET("/pokemon/id/:id/moves", handler.GetPokemonMoves)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25/moves?version_group=scarlet-violet&method=level-up", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                   `json:"success"`
+		Data    types.LearnsetResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, "scarlet-violet", response.Data.VersionGroup)
+	assert.Len(t, response.Data.Moves, 2)
+	assert.Equal(t, "thunder-shock", response.Data.Moves[0].Name)
+	assert.Equal(t, 1, response.Data.Moves[0].Level)
+	assert.Equal(t, "thunder", response.Data.Moves[1].Name)
+	assert.Equal(t, 110, *response.Data.Moves[1].Power)
+}
+
+func TestGetPokemonMoves_Errors(t *testing.T) {
+	router := setupTestRouter()
+	handler := newMovesTestHandler()
+
+	router.GET("/pokemon/id/:id/moves", handler.GetPokemonMoves)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/pokemon/id/abc/moves?version_group=scarlet-violet", http.StatusBadRequest},
+		{"/pokemon/id/25/moves", http.StatusBadRequest},
+		{"/pokemon/id/150/moves?version_group=scarlet-violet", http.StatusNotFound},
+		{"/pokemon/id/25/moves?version_group=red-blue", http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
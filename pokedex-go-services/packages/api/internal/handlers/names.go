@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+	maxSuggestions           = 5
+)
+
+// WithNames sets the name index used to resolve, complete and suggest names
+func (h *PokemonHandler) WithNames(names *index.NameIndex) *PokemonHandler {
+	h.names = names
+	return h
+}
+
+// Autocomplete returns the Pokémon whose names start with the query
+func (h *PokemonHandler) Autocomplete(c *gin.Context) {
+	query := c.Query("q")
+	if strings.TrimSpace(query) == "" {
+		respondInvalidParam(c, "q", i18n.CodeQueryRequired)
+		return
+	}
+
+	limit, err := queryInt(c, "limit", defaultAutocompleteLimit)
+	if err != nil || limit < 1 || limit > maxAutocompleteLimit {
+		respondInvalidParam(c, "limit", i18n.CodeInvalidLimit, maxAutocompleteLimit)
+		return
+	}
+
+	if h.names == nil || h.names.Len() == 0 {
+		respondError(c, http.StatusServiceUnavailable, i18n.CodeNameIndexUnavailable)
+		return
+	}
+
+	respondData(c, h.names.Prefix(query, limit))
+}
+
+// findByName searches for a Pokémon by name. Names known to the name index
+// are resolved to their species ID, so "Mr Mime" or "nidoran f" work; other
+// names, such as alternate forms, are passed on to the source.
+func (h *PokemonHandler) findByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	if h.names != nil {
+		if match, ok := h.names.Lookup(name); ok {
+			return h.source.GetPokemonByID(ctx, match.ID)
+		}
+	}
+
+	name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	return h.source.GetPokemonByName(ctx, name)
+}
+
+// respondNameError writes the error of a search by name, suggesting similar
+// names when the Pokémon does not exist
+func (h *PokemonHandler) respondNameError(c *gin.Context, name string, err error) {
+	if !errors.Is(err, services.ErrNotFound) || h.names == nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondErrorDetails(c, http.StatusNotFound, i18n.CodePokemonNotFound, gin.H{
+		"suggestions": h.names.Suggest(name, maxSuggestions),
+	})
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func newNamesTestHandler() *PokemonHandler {
+	mrMime := &types.Pokemon{ID: 122, Name: "mr-mime"}
+
+	names := index.NewNameIndex()
+	names.Add(25, "pikachu", "en")
+	names.Add(122, "mr-mime", "en")
+	names.Add(731, "pikipek", "en")
+
+	return NewPokemonHandler(services.NewMemorySource(newPikachu(), mrMime)).WithNames(names)
+}
+
+func TestAutocomplete(t *testing.T) {
+	router := setupTestRouter()
+	handler := newNamesTestHandler()
+
+	router.GET("/pokemon/autocomplete", handler.Autocomplete)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/autocomplete?q=Pik", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                `json:"success"`
+		Data    []types.PokemonName `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, []types.PokemonName{{ID: 25, Name: "pikachu", Language: "en"}, {ID: 731, Name: "pikipek", Language: "en"}}, response.Data)
+}
+
+func TestAutocomplete_Errors(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/pokemon/autocomplete", newNamesTestHandler().Autocomplete)
+	router.GET("/empty/autocomplete", newTestHandler().WithNames(index.NewNameIndex()).Autocomplete)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/pokemon/autocomplete", http.StatusBadRequest},
+		{"/pokemon/autocomplete?q=pik&limit=0", http.StatusBadRequest},
+		{"/empty/autocomplete?q=pik", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
+
+func TestSearchPokemon_NormalizedName(t *testing.T) {
+	router := setupTestRouter()
+	handler := newNamesTestHandler()
+
+	router.GET("/search", handler.SearchPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=Mr.+Mime", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "mr-mime", decodeData(t, w).Name)
+}
+
+func TestSearchPokemon_Suggestions(t *testing.T) {
+	router := setupTestRouter()
+	handler := newNamesTestHandler()
+
+	router.GET("/search", handler.SearchPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?q=pikach", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Suggestions []types.PokemonName `json:"suggestions"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, string(i18n.CodePokemonNotFound), response.Error.Code)
+	assert.NotEmpty(t, response.Error.Details.Suggestions)
+	assert.Equal(t, "pikachu", response.Error.Details.Suggestions[0].Name)
+}
+
+func TestGetPokemonByName_NormalizedName(t *testing.T) {
+	router := setupTestRouter()
+	handler := newNamesTestHandler()
+
+	router.GET("/pokemon/name/:name", handler.GetPokemonByName)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/name/mr%20mime", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 122, decodeData(t, w).ID)
+}
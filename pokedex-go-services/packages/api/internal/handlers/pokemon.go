@@ -1,136 +1,166 @@
 package handlers
 
 import (
-	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"pokedexia-backend/internal/config"
-	"pokedexia-backend/internal/types"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/index"
 	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
 )
 
 // PokemonHandler represents the handler for Pokémon endpoints
 type PokemonHandler struct {
-	pokeAPIService *services.PokeAPIService
+	source services.PokemonSource
+	index  *index.Index
+	names  *index.NameIndex
+	ids    *services.PokemonIDs
 }
 
 // NewPokemonHandler creates a new instance of the handler
-func NewPokemonHandler(cfg *config.Config) *PokemonHandler {
+func NewPokemonHandler(source services.PokemonSource) *PokemonHandler {
 	return &PokemonHandler{
-		pokeAPIService: services.NewPokeAPIService(cfg),
+		source: source,
+		ids:    services.NewPokemonIDs(services.MaxPokemonID),
 	}
 }
 
+// WithIDs sets the set of valid Pokémon IDs, replacing the built-in bounds
+func (h *PokemonHandler) WithIDs(ids *services.PokemonIDs) *PokemonHandler {
+	h.ids = ids
+	return h
+}
+
 // GetPokemonByID searches for a Pokémon by ID
 func (h *PokemonHandler) GetPokemonByID(c *gin.Context) {
-	idStr := c.Param("id")
-	
 	// Validate the ID
-	id, err := h.pokeAPIService.ValidatePokemonID(idStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+	id, ok := h.validateID(c, "id", c.Param("id"))
+	if !ok {
+		return
+	}
+
+	lang, ok := requestLanguage(c)
+	if !ok {
 		return
 	}
 
 	// Search for the Pokémon
-	pokemon, err := h.pokeAPIService.GetPokemonByID(id)
+	pokemon, err := h.source.GetPokemonByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Erro ao buscar Pokémon: " + err.Error(),
-		})
+		respondServiceError(c, err)
 		return
 	}
 
 	// Transform to the response format
-	response := h.pokeAPIService.TransformPokemonToResponse(pokemon)
+	response := h.source.TransformPokemonToResponse(pokemon)
+	h.indexPokemon(c.Request.Context(), pokemon, response)
+	h.localize(c, pokemon, response, lang)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    response,
-	})
+	respondData(c, response)
 }
 
 // GetPokemonByName searches for a Pokémon by name
 func (h *PokemonHandler) GetPokemonByName(c *gin.Context) {
 	name := c.Param("name")
-	
+
 	if name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Nome do Pokémon é obrigatório",
-		})
+		respondInvalidParam(c, "name", i18n.CodeNameRequired)
+		return
+	}
+
+	lang, ok := requestLanguage(c)
+	if !ok {
 		return
 	}
 
 	// Search for the Pokémon
-	pokemon, err := h.pokeAPIService.GetPokemonByName(name)
+	pokemon, err := h.findByName(c.Request.Context(), name)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Erro ao buscar Pokémon: " + err.Error(),
-		})
+		h.respondNameError(c, name, err)
 		return
 	}
 
 	// Transform to the response format
-	response := h.pokeAPIService.TransformPokemonToResponse(pokemon)
+	response := h.source.TransformPokemonToResponse(pokemon)
+	h.indexPokemon(c.Request.Context(), pokemon, response)
+	h.localize(c, pokemon, response, lang)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    response,
-	})
+	respondData(c, response)
 }
 
 // SearchPokemon searches for a Pokémon by ID or name
 func (h *PokemonHandler) SearchPokemon(c *gin.Context) {
 	query := c.Query("q")
-	
+
 	if query == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Parâmetro 'q' é obrigatório",
-		})
+		respondInvalidParam(c, "q", i18n.CodeQueryRequired)
+		return
+	}
+
+	lang, ok := requestLanguage(c)
+	if !ok {
 		return
 	}
 
 	var pokemon *types.Pokemon
 	var err error
 
-		// Try to convert to ID first
-	if id, err := strconv.Atoi(query); err == nil {
+	// Try to convert to ID first
+	if _, convErr := strconv.Atoi(query); convErr == nil {
 		// It's a number, search by ID
-		if id < 1 || id > 1025 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "ID deve estar entre 1 e 1025",
-			})
+		id, ok := h.validateID(c, "q", query)
+		if !ok {
+			return
+		}
+		pokemon, err = h.source.GetPokemonByID(c.Request.Context(), id)
+		if err != nil {
+			respondServiceError(c, err)
 			return
 		}
-		pokemon, err = h.pokeAPIService.GetPokemonByID(id)
 	} else {
 		// It's a name, search by name
-		pokemon, err = h.pokeAPIService.GetPokemonByName(query)
-	}
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Erro ao buscar Pokémon: " + err.Error(),
-		})
-		return
+		pokemon, err = h.findByName(c.Request.Context(), query)
+		if err != nil {
+			h.respondNameError(c, query, err)
+			return
+		}
 	}
 
 	// Transform to the response format
-	response := h.pokeAPIService.TransformPokemonToResponse(pokemon)
+	response := h.source.TransformPokemonToResponse(pokemon)
+	h.indexPokemon(c.Request.Context(), pokemon, response)
+	h.localize(c, pokemon, response, lang)
 
-	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    response,
-	})
+	respondData(c, response)
+}
+
+// validateID parses a Pokémon ID from a request parameter. It writes the
+// error response and returns false when the ID is not valid.
+func (h *PokemonHandler) validateID(c *gin.Context, param, value string) (int, bool) {
+	id, err := h.ids.Validate(value)
+	if err != nil {
+		respondInvalidParam(c, param, i18n.CodeInvalidID, h.ids.Max())
+		return 0, false
+	}
+	return id, true
 }
 
 // HealthCheck checks if the API is working
 func (h *PokemonHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"status":  "ok",
-		"message": "PokedexIA API está funcionando",
-	})
-} 
\ No newline at end of file
+		"message": i18n.Message(messageLanguage(c), i18n.CodeAPIHealthy),
+	}
+
+	// Report the PokeAPI connection when the source knows about it
+	if reporter, ok := h.source.(services.HealthReporter); ok {
+		upstream := reporter.UpstreamHealth()
+		if upstream.CircuitBreaker != nil && upstream.CircuitBreaker.State != services.BreakerClosed.String() {
+			response["status"] = "degraded"
+		}
+		response["upstream"] = upstream
+	}
+
+	respondData(c, response)
+}
@@ -1,26 +1,79 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
-	"pokedexia-backend/internal/config"
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/middleware"
+	"pokedexia-backend/internal/origin"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
 )
 
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.TrackOrigin())
 	return router
 }
 
+func newPikachu() *types.Pokemon {
+	pokemon := &types.Pokemon{
+		ID:     25,
+		Name:   "pikachu",
+		Height: 4,
+		Weight: 60,
+		Types:  make([]types.Type, 1),
+		Stats:  make([]types.Stat, 1),
+		Sprites: types.Sprites{
+			FrontDefault: "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png",
+		},
+	}
+	pokemon.Types[0].Slot = 1
+	pokemon.Types[0].Type.Name = "electric"
+	pokemon.Stats[0].BaseStat = 35
+	pokemon.Stats[0].Stat.Name = "hp"
+	return pokemon
+}
+
+func newTestHandler() *PokemonHandler {
+	return NewPokemonHandler(services.NewMemorySource(newPikachu()))
+}
+
+func decodeData(t *testing.T, w *httptest.ResponseRecorder) types.PokemonResponse {
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    types.PokemonResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	return response.Data
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) types.ErrorBody {
+	var response types.Envelope
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.False(t, response.Success)
+	if assert.NotNil(t, response.Error) {
+		return *response.Error
+	}
+	return types.ErrorBody{}
+}
+
 func TestHealthCheck(t *testing.T) {
 	router := setupTestRouter()
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	handler := newTestHandler()
 
 	router.GET("/health", handler.HealthCheck)
 
@@ -30,24 +83,59 @@ func TestHealthCheck(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	var response map[string]interface{}
+	var response struct {
+		Data struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"data"`
+	}
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 
-	assert.Equal(t, "ok", response["status"])
-	assert.Equal(t, "PokedexIA API está funcionando", response["message"])
+	assert.Equal(t, "ok", response.Data.Status)
+	assert.Equal(t, i18n.Message("", i18n.CodeAPIHealthy), response.Data.Message)
 }
 
-// TestGetPokemonByID_ValidID is skipped because it requires mocking the service layer
-// In a real scenario, we would create a mock service to test this properly
 func TestGetPokemonByID_ValidID(t *testing.T) {
-	t.Skip("This test requires mocking the service layer")
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	data := decodeData(t, w)
+	assert.Equal(t, 25, data.ID)
+	assert.Equal(t, "pikachu", data.Name)
+	assert.Equal(t, []string{"electric"}, data.Types)
+	assert.Equal(t, 35, data.Stats.HP)
+}
+
+func TestGetPokemonByID_NotFound(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/26", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response, "error")
 }
 
 func TestGetPokemonByID_InvalidID(t *testing.T) {
 	router := setupTestRouter()
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	handler := newTestHandler()
 
 	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
 
@@ -70,18 +158,14 @@ func TestGetPokemonByID_InvalidID(t *testing.T) {
 
 			assert.Equal(t, tc.expected, w.Code)
 
-			var response map[string]interface{}
-			err := json.Unmarshal(w.Body.Bytes(), &response)
-			assert.NoError(t, err)
-			assert.Contains(t, response, "error")
+			assert.Equal(t, string(i18n.CodeInvalidID), decodeError(t, w).Code)
 		})
 	}
 }
 
 func TestGetPokemonByName_EmptyName(t *testing.T) {
 	router := setupTestRouter()
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	handler := newTestHandler()
 
 	router.GET("/pokemon/name/:name", handler.GetPokemonByName)
 
@@ -99,8 +183,7 @@ func TestGetPokemonByName_EmptyName(t *testing.T) {
 
 func TestSearchPokemon_EmptyQuery(t *testing.T) {
 	router := setupTestRouter()
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	handler := newTestHandler()
 
 	router.GET("/pokemon/search", handler.SearchPokemon)
 
@@ -118,8 +201,7 @@ func TestSearchPokemon_EmptyQuery(t *testing.T) {
 
 func TestSearchPokemon_InvalidID(t *testing.T) {
 	router := setupTestRouter()
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	handler := newTestHandler()
 
 	router.GET("/pokemon/search", handler.SearchPokemon)
 
@@ -149,38 +231,339 @@ func TestSearchPokemon_InvalidID(t *testing.T) {
 	}
 }
 
+func TestSearchPokemon_ByID(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/search", handler.SearchPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/search?q=25", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "pikachu", decodeData(t, w).Name)
+}
+
+func TestSearchPokemon_ByName(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/search", handler.SearchPokemon)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/search?q=Pikachu", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 25, decodeData(t, w).ID)
+}
+
+func TestSearchPokemon_NotFound(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/search", handler.SearchPokemon)
+
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"unknown ID", "26"},
+		{"unknown name", "raichu"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/pokemon/search?q="+tc.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Contains(t, response, "error")
+		})
+	}
+}
+
 func TestNewPokemonHandler(t *testing.T) {
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+	source := services.NewMemorySource()
+	handler := NewPokemonHandler(source)
 
 	assert.NotNil(t, handler)
-	assert.NotNil(t, handler.pokeAPIService)
+	assert.Equal(t, source, handler.source)
 }
 
-// Mock tests for successful scenarios (these would require mocking the service layer)
-func TestGetPokemonByID_Success_Mock(t *testing.T) {
-	// This test would require creating a mock service
-	// For now, we'll just test the handler creation
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+func TestGetPokemonByName_Success(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/name/:name", handler.GetPokemonByName)
 
-	assert.NotNil(t, handler)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/name/pikachu", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	data := decodeData(t, w)
+	assert.Equal(t, 25, data.ID)
+	assert.Equal(t, "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png", data.ImageURL)
 }
 
-func TestGetPokemonByName_Success_Mock(t *testing.T) {
-	// This test would require creating a mock service
-	// For now, we'll just test the handler creation
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+func TestGetPokemonByName_NotFound(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/name/:name", handler.GetPokemonByName)
 
-	assert.NotNil(t, handler)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/name/raichu", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Contains(t, response, "error")
 }
 
-func TestSearchPokemon_Success_Mock(t *testing.T) {
-	// This test would require creating a mock service
-	// For now, we'll just test the handler creation
-	cfg := &config.Config{PokeAPIBaseURL: "https://pokeapi.co/api/v2"}
-	handler := NewPokemonHandler(cfg)
+// errorSource is a PokemonSource that always fails with the given error
+type errorSource struct {
+	services.PokemonSource
+	err error
+}
 
-	assert.NotNil(t, handler)
-} 
\ No newline at end of file
+func (s *errorSource) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	return nil, s.err
+}
+
+func (s *errorSource) GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	return nil, s.err
+}
+
+func TestServiceErrorMapping(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+		code     i18n.Code
+	}{
+		{"not found", &services.UpstreamError{Path: "/pokemon/25", StatusCode: 404, Kind: services.ErrNotFound}, http.StatusNotFound, i18n.CodePokemonNotFound},
+		{"rate limited", &services.UpstreamError{Path: "/pokemon/25", StatusCode: 429, Kind: services.ErrRateLimited}, http.StatusTooManyRequests, i18n.CodeUpstreamRateLimited},
+		{"upstream unavailable", &services.UpstreamError{Path: "/pokemon/25", StatusCode: 503, Kind: services.ErrUpstreamUnavailable}, http.StatusServiceUnavailable, i18n.CodeUpstreamUnavailable},
+		{"decode", &services.UpstreamError{Path: "/pokemon/25", Kind: services.ErrDecode}, http.StatusBadGateway, i18n.CodeUpstreamInvalidResponse},
+		{"timeout", &services.UpstreamError{Path: "/pokemon/25", Kind: services.ErrUpstreamUnavailable, Cause: context.DeadlineExceeded}, http.StatusGatewayTimeout, i18n.CodeUpstreamTimeout},
+		{"wrapped", fmt.Errorf("lookup: %w", services.ErrNotFound), http.StatusNotFound, i18n.CodePokemonNotFound},
+		{"unknown", errors.New("Get \"http://internal:8080/pokemon/25\": boom"), http.StatusInternalServerError, i18n.CodeInternalError},
+	}
+
+	paths := []string{"/pokemon/id/25", "/pokemon/name/pikachu", "/pokemon/search?q=25", "/pokemon/search?q=pikachu"}
+
+	for _, tc := range testCases {
+		router := setupTestRouter()
+		handler := NewPokemonHandler(&errorSource{err: tc.err})
+
+		router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+		router.GET("/pokemon/name/:name", handler.GetPokemonByName)
+		router.GET("/pokemon/search", handler.SearchPokemon)
+
+		for _, path := range paths {
+			t.Run(tc.name+" "+path, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest("GET", path, nil)
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tc.expected, w.Code)
+
+				body := decodeError(t, w)
+				assert.Equal(t, string(tc.code), body.Code)
+				// Internal error details never reach the client
+				assert.NotContains(t, body.Message, "internal:8080")
+			})
+		}
+	}
+}
+
+func TestErrorMessageLanguage(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	testCases := []struct {
+		name   string
+		path   string
+		header string
+		lang   string
+	}{
+		{"default", "/pokemon/id/150", "", i18n.DefaultMessages},
+		{"Accept-Language", "/pokemon/id/150", "es-AR,es;q=0.9", "es"},
+		{"first translated language", "/pokemon/id/150", "ja, en;q=0.5", "en"},
+		{"lang parameter", "/pokemon/id/150?lang=en", "es", "en"},
+		{"untranslated lang parameter", "/pokemon/id/150?lang=ko", "", "en"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set("Accept-Language", tc.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+
+			body := decodeError(t, w)
+			assert.Equal(t, string(i18n.CodePokemonNotFound), body.Code)
+			assert.Equal(t, i18n.Message(tc.lang, i18n.CodePokemonNotFound), body.Message)
+		})
+	}
+}
+
+func TestGetPokemonByID_ClientDisconnected(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/pokemon/id/25", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, statusClientClosedRequest, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+// reportingSource is a PokemonSource that reports a fixed upstream state
+type reportingSource struct {
+	services.PokemonSource
+	health types.UpstreamHealth
+}
+
+func (s *reportingSource) UpstreamHealth() types.UpstreamHealth {
+	return s.health
+}
+
+func TestHealthCheck_ReportsCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		state    string
+		expected string
+	}{
+		{"closed", "ok"},
+		{"open", "degraded"},
+		{"half-open", "degraded"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.state, func(t *testing.T) {
+			router := setupTestRouter()
+			source := &reportingSource{
+				PokemonSource: services.NewMemorySource(),
+				health: types.UpstreamHealth{
+					CircuitBreaker: &types.BreakerStatus{State: tc.state},
+				},
+			}
+			handler := NewPokemonHandler(source)
+
+			router.GET("/health", handler.HealthCheck)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/health", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response struct {
+				Data struct {
+					Status   string               `json:"status"`
+					Upstream types.UpstreamHealth `json:"upstream"`
+				} `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expected, response.Data.Status)
+			assert.Equal(t, tc.state, response.Data.Upstream.CircuitBreaker.State)
+		})
+	}
+}
+
+func TestResponseMeta(t *testing.T) {
+	router := setupTestRouter()
+	cache := services.NewCachedSource(services.NewMemorySource(newPikachu()), 10, time.Hour)
+	handler := NewPokemonHandler(cache)
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+
+	testCases := []struct {
+		name   string
+		path   string
+		cached bool
+		source string
+	}{
+		{"miss", "/pokemon/id/25", false, origin.SourceMemory},
+		{"hit", "/pokemon/id/25", true, origin.SourceCache},
+		{"error", "/pokemon/id/abc", false, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set(middleware.RequestIDHeader, "req-"+tc.name)
+			router.ServeHTTP(w, req)
+
+			var response types.Envelope
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "req-"+tc.name, response.Meta.RequestID)
+			assert.Equal(t, "req-"+tc.name, w.Header().Get(middleware.RequestIDHeader))
+			assert.Equal(t, tc.cached, response.Meta.Cached)
+			assert.Equal(t, tc.source, response.Meta.Source)
+		})
+	}
+}
+
+func TestValidateID_DiscoveredIDs(t *testing.T) {
+	router := setupTestRouter()
+	ids := services.NewPokemonIDs(services.MaxPokemonID)
+	ids.Set(1030, []int{10033})
+	handler := newTestHandler().WithIDs(ids)
+
+	router.GET("/pokemon/id/:id", handler.GetPokemonByID)
+	router.GET("/pokemon/search", handler.SearchPokemon)
+
+	testCases := []struct {
+		path     string
+		expected int
+	}{
+		{"/pokemon/id/1030", http.StatusNotFound},
+		{"/pokemon/id/1031", http.StatusBadRequest},
+		{"/pokemon/id/10033", http.StatusNotFound},
+		{"/pokemon/id/10034", http.StatusBadRequest},
+		{"/pokemon/search?q=1030", http.StatusNotFound},
+		{"/pokemon/search?q=1031", http.StatusBadRequest},
+		{"/pokemon/search?q=10033", http.StatusNotFound},
+		{"/pokemon/search?q=10034", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tc.path, nil)
+			req.Header.Set("Accept-Language", "en")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected == http.StatusBadRequest {
+				assert.Equal(t, i18n.Message("en", i18n.CodeInvalidID, 1030), decodeError(t, w).Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/middleware"
+	"pokedexia-backend/internal/origin"
+	"pokedexia-backend/internal/types"
+)
+
+// respondData writes a successful response with the data
+func respondData(c *gin.Context, data any) {
+	respond(c, http.StatusOK, types.Envelope{Success: true, Data: data})
+}
+
+// respondPage writes a successful response with a page of a listing
+func respondPage(c *gin.Context, data any, pagination types.Pagination) {
+	envelope := types.Envelope{Success: true, Data: data}
+	envelope.Meta.Pagination = &pagination
+	respond(c, http.StatusOK, envelope)
+}
+
+// respond fills in the metadata of the envelope and writes it
+func respond(c *gin.Context, status int, envelope types.Envelope) {
+	tracked := origin.From(c.Request.Context())
+
+	envelope.Meta.RequestID = middleware.GetRequestID(c)
+	envelope.Meta.Cached = tracked.Cached()
+	envelope.Meta.Source = tracked.Source()
+
+	c.JSON(status, envelope)
+}
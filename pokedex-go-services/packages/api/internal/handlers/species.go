@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+// GetPokemonSpecies returns the species data of a Pokémon: names, genus,
+// flavor text, generation and legendary/mythical flags. The texts in the
+// requested language are also returned on their own.
+func (h *PokemonHandler) GetPokemonSpecies(c *gin.Context) {
+	lang, ok := requestLanguage(c)
+	if !ok {
+		return
+	}
+
+	species, ok := h.lookupSpecies(c)
+	if !ok {
+		return
+	}
+
+	response := services.TransformSpeciesToResponse(species)
+	if lang != "" {
+		services.LocalizeSpecies(response, lang)
+	}
+
+	respondData(c, response)
+}
+
+// GetPokemonEvolutions returns the fully resolved evolution chain of a Pokémon
+func (h *PokemonHandler) GetPokemonEvolutions(c *gin.Context) {
+	species, ok := h.lookupSpecies(c)
+	if !ok {
+		return
+	}
+
+	chain, err := h.source.GetEvolutionChain(c.Request.Context(), services.ResourceID(species.EvolutionChain.URL))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondData(c, services.TransformEvolutionChainToResponse(chain))
+}
+
+// lookupSpecies resolves the species of the Pokémon in the :id parameter. The
+// Pokémon is looked up first, as forms point to their base species. It writes
+// the error response and returns false on failure.
+func (h *PokemonHandler) lookupSpecies(c *gin.Context) (*types.PokemonSpecies, bool) {
+	id, ok := h.validateID(c, "id", c.Param("id"))
+	if !ok {
+		return nil, false
+	}
+
+	pokemon, err := h.source.GetPokemonByID(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return nil, false
+	}
+
+	species, err := h.speciesOf(c.Request.Context(), pokemon)
+	if err != nil {
+		respondServiceError(c, err)
+		return nil, false
+	}
+
+	return species, true
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/types"
+)
+
+func newSpeciesTestHandler() *PokemonHandler {
+	pikachu := newPikachu()
+	pikachu.Species = types.NamedResource{Name: "pikachu", URL: "https://pokeapi.co/api/v2/pokemon-species/25/"}
+
+	source := services.NewMemorySource(pikachu)
+	source.AddSpecies(&types.PokemonSpecies{
+		ID:         25,
+		Name:       "pikachu",
+		Generation: types.NamedResource{Name: "generation-i"},
+		Genera: []types.Genus{
+			{Genus: "Mouse Pokémon", Language: types.NamedResource{Name: "en"}},
+		},
+		EvolvesFromSpecies: &types.NamedResource{Name: "pichu"},
+		EvolutionChain:     types.APIResource{URL: "https://pokeapi.co/api/v2/evolution-chain/10/"},
+	})
+	source.AddEvolutionChain(&types.EvolutionChain{
+		ID: 10,
+		Chain: types.ChainLink{
+			IsBaby:  true,
+			Species: types.NamedResource{Name: "pichu", URL: "https://pokeapi.co/api/v2/pokemon-species/172/"},
+			EvolvesTo: []types.ChainLink{{
+				Species:          types.NamedResource{Name: "pikachu", URL: "https://pokeapi.co/api/v2/pokemon-species/25/"},
+				EvolutionDetails: []types.EvolutionDetail{{Trigger: types.NamedResource{Name: "level-up"}, MinHappiness: intPtr(220)}},
+			}},
+		},
+	})
+	return NewPokemonHandler(source)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestGetPokemonSpecies(t *testing.T) {
+	router := setupTestRouter()
+	handler := newSpeciesTestHandler()
+
+	router.GET("/pokemon/id/:id/species", handler.GetPokemonSpecies)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25/species", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    types.SpeciesResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, "generation-i", response.Data.Generation)
+	assert.Equal(t, "Mouse Pokémon", response.Data.Genus["en"])
+	assert.Equal(t, "pichu", response.Data.EvolvesFrom)
+}
+
+func TestGetPokemonEvolutions(t *testing.T) {
+	router := setupTestRouter()
+	handler := newSpeciesTestHandler()
+
+	router.GET("/pokemon/id/:id/evolutions", handler.GetPokemonEvolutions)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25/evolutions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                         `json:"success"`
+		Data    types.EvolutionChainResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, 172, response.Data.Chain.SpeciesID)
+	assert.True(t, response.Data.Chain.IsBaby)
+	assert.Len(t, response.Data.Chain.EvolvesTo, 1)
+
+	pikachu := response.Data.Chain.EvolvesTo[0]
+	assert.Equal(t, "pikachu", pikachu.Species)
+	assert.Len(t, pikachu.Conditions, 1)
+	assert.Equal(t, "level-up", pikachu.Conditions[0].Trigger)
+	assert.Equal(t, 220, *pikachu.Conditions[0].MinHappiness)
+}
+
+func TestGetPokemonSpecies_Errors(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id/species", handler.GetPokemonSpecies)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/pokemon/id/abc/species", http.StatusBadRequest},
+		{"/pokemon/id/150/species", http.StatusNotFound},
+		// Pikachu exists but its species was never added
+		{"/pokemon/id/25/species", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/services"
+)
+
+// GetPokemonWeaknesses returns the type matchups of a Pokémon when it is attacked
+func (h *PokemonHandler) GetPokemonWeaknesses(c *gin.Context) {
+	id, ok := h.validateID(c, "id", c.Param("id"))
+	if !ok {
+		return
+	}
+
+	pokemon, err := h.source.GetPokemonByID(c.Request.Context(), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	matchups, err := services.TypeMatchups(pokemon)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+
+	respondData(c, matchups)
+}
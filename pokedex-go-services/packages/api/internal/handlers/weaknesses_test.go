@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/types"
+)
+
+func TestGetPokemonWeaknesses(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id/weaknesses", handler.GetPokemonWeaknesses)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/pokemon/id/25/weaknesses", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Success bool                     `json:"success"`
+		Data    types.WeaknessesResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.True(t, response.Success)
+	assert.Equal(t, []string{"electric"}, response.Data.Types)
+	assert.Equal(t, []string{"ground"}, response.Data.Double)
+	assert.Equal(t, []string{"electric", "flying", "steel"}, response.Data.Half)
+	assert.Empty(t, response.Data.Immune)
+	assert.Equal(t, 1.0, response.Data.Multipliers["fire"])
+}
+
+func TestGetPokemonWeaknesses_Errors(t *testing.T) {
+	router := setupTestRouter()
+	handler := newTestHandler()
+
+	router.GET("/pokemon/id/:id/weaknesses", handler.GetPokemonWeaknesses)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/pokemon/id/0/weaknesses", http.StatusBadRequest},
+		{"/pokemon/id/150/weaknesses", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tt.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.code, w.Code, tt.path)
+	}
+}
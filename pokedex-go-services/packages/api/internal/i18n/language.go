@@ -0,0 +1,125 @@
+// Package i18n negotiates the language of responses and picks localized
+// values, falling back to English when a translation is missing.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language used when a translation is missing
+const Default = "en"
+
+// languages maps lowercase language tags to the PokeAPI language codes they
+// are served in. PokeAPI lists pt-BR but has no Portuguese data yet, so
+// Portuguese falls back to English until it does.
+var languages = map[string]string{
+	"en":      "en",
+	"pt":      "pt-BR",
+	"pt-br":   "pt-BR",
+	"pt-pt":   "pt-BR",
+	"es":      "es",
+	"fr":      "fr",
+	"de":      "de",
+	"it":      "it",
+	"cs":      "cs",
+	"ko":      "ko",
+	"ja":      "ja",
+	"ja-jp":   "ja",
+	"ja-hrkt": "ja-Hrkt",
+	"roomaji": "roomaji",
+	"zh":      "zh-Hans",
+	"zh-cn":   "zh-Hans",
+	"zh-sg":   "zh-Hans",
+	"zh-hans": "zh-Hans",
+	"zh-tw":   "zh-Hant",
+	"zh-hk":   "zh-Hant",
+	"zh-hant": "zh-Hant",
+}
+
+// alternates lists other codes that are close enough to stand in for a language
+var alternates = map[string][]string{
+	"ja":      {"ja-Hrkt"},
+	"ja-Hrkt": {"ja"},
+}
+
+// Parse returns the PokeAPI language code of a language tag such as "pt-BR" or "es"
+func Parse(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if code, ok := languages[tag]; ok {
+		return code, true
+	}
+
+	// Fall back to the primary subtag, e.g. "es-MX" is served as "es"
+	if base, _, found := strings.Cut(tag, "-"); found {
+		code, ok := languages[base]
+		return code, ok
+	}
+	return "", false
+}
+
+// Negotiate returns the supported language the client prefers most according
+// to an Accept-Language header, or an empty string if none is supported
+func Negotiate(acceptLanguage string) string {
+	return negotiate(acceptLanguage, func(string) bool { return true })
+}
+
+// negotiate returns the language the client prefers most among the ones
+// accepted by the filter
+func negotiate(acceptLanguage string, accept func(code string) bool) string {
+	type candidate struct {
+		code    string
+		quality float64
+	}
+	var candidates []candidate
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		if code, ok := Parse(tag); ok && accept(code) {
+			candidates = append(candidates, candidate{code, quality})
+		}
+	}
+
+	// Keep the header order between languages of equal quality
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].code
+}
+
+// Fallbacks returns the languages to try, in order, when looking for a translation
+func Fallbacks(code string) []string {
+	chain := append([]string{code}, alternates[code]...)
+	if code != Default {
+		chain = append(chain, Default)
+	}
+	return chain
+}
+
+// Select returns the value for the language, falling back to close languages
+// and then English
+func Select[T any](values map[string]T, code string) (T, bool) {
+	for _, lang := range Fallbacks(code) {
+		if value, ok := values[lang]; ok {
+			return value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
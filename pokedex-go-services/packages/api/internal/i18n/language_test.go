@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"pt-BR":   "pt-BR",
+		"pt_br":   "pt-BR",
+		"pt":      "pt-BR",
+		"es-MX":   "es",
+		"JA":      "ja",
+		"ja-Hrkt": "ja-Hrkt",
+		"zh-TW":   "zh-Hant",
+		"zh":      "zh-Hans",
+	}
+
+	for tag, expected := range tests {
+		if code, ok := Parse(tag); !ok || code != expected {
+			t.Errorf("Parse(%q) = %q, %v, expected %q", tag, code, ok, expected)
+		}
+	}
+
+	for _, tag := range []string{"", "xx", "klingon-US"} {
+		if code, ok := Parse(tag); ok {
+			t.Errorf("Expected %q to be unsupported, got %q", tag, code)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]string{
+		"":                                    "",
+		"*":                                   "",
+		"pt-BR,pt;q=0.9,en-US;q=0.8,en;q=0.7": "pt-BR",
+		"xx, es;q=0.5, fr;q=0.8":              "fr",
+		"de;q=0.5, ja":                        "ja",
+		"en;q=0, es;q=0.1":                    "es",
+		"fr;q=abc, it":                        "it",
+	}
+
+	for header, expected := range tests {
+		if got := Negotiate(header); got != expected {
+			t.Errorf("Negotiate(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	if got := Fallbacks("pt-BR"); !reflect.DeepEqual(got, []string{"pt-BR", "en"}) {
+		t.Errorf("Unexpected fallbacks %v", got)
+	}
+	if got := Fallbacks("ja"); !reflect.DeepEqual(got, []string{"ja", "ja-Hrkt", "en"}) {
+		t.Errorf("Unexpected fallbacks %v", got)
+	}
+	if got := Fallbacks("en"); !reflect.DeepEqual(got, []string{"en"}) {
+		t.Errorf("Unexpected fallbacks %v", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	names := map[string]string{"en": "Pikachu", "ja-Hrkt": "ピカチュウ", "es": "Pikachu"}
+
+	tests := map[string]string{
+		"es":    "Pikachu",
+		"ja":    "ピカチュウ",
+		"pt-BR": "Pikachu",
+	}
+	for lang, expected := range tests {
+		if got, ok := Select(names, lang); !ok || got != expected {
+			t.Errorf("Select(%q) = %q, %v, expected %q", lang, got, ok, expected)
+		}
+	}
+
+	if _, ok := Select(map[string]string{"fr": "Pikachu"}, "de"); ok {
+		t.Error("Expected no value without an English fallback")
+	}
+}
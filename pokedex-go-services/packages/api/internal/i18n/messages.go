@@ -0,0 +1,177 @@
+package i18n
+
+import "fmt"
+
+// Code identifies a message regardless of its language. Codes are part of
+// the API contract: clients may rely on them, so they never change.
+type Code string
+
+// Error codes
+const (
+	CodeInvalidID               Code = "INVALID_ID"
+	CodeNameRequired            Code = "NAME_REQUIRED"
+	CodeQueryRequired           Code = "QUERY_REQUIRED"
+	CodeVersionGroupRequired    Code = "VERSION_GROUP_REQUIRED"
+	CodeUnsupportedLanguage     Code = "UNSUPPORTED_LANGUAGE"
+	CodeInvalidPage             Code = "INVALID_PAGE"
+	CodeInvalidLimit            Code = "INVALID_LIMIT"
+	CodeInvalidGeneration       Code = "INVALID_GENERATION"
+	CodeInvalidSort             Code = "INVALID_SORT"
+	CodeInvalidType             Code = "INVALID_TYPE"
+	CodeNameIndexUnavailable    Code = "NAME_INDEX_UNAVAILABLE"
+	CodeIndexUnavailable        Code = "INDEX_UNAVAILABLE"
+	CodeUnauthorized            Code = "UNAUTHORIZED"
+	CodePokemonNotFound         Code = "POKEMON_NOT_FOUND"
+	CodeUpstreamTimeout         Code = "UPSTREAM_TIMEOUT"
+	CodeUpstreamRateLimited     Code = "UPSTREAM_RATE_LIMITED"
+	CodeUpstreamUnavailable     Code = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamInvalidResponse Code = "UPSTREAM_INVALID_RESPONSE"
+	CodeMoveNotFound            Code = "MOVE_NOT_FOUND"
+	CodeInternalError           Code = "INTERNAL_ERROR"
+)
+
+// Status codes
+const (
+	CodeAPIHealthy Code = "API_HEALTHY"
+)
+
+// DefaultMessages is the language of messages when the client does not ask for one
+const DefaultMessages = "pt-BR"
+
+// catalog holds the messages of each language. Arguments are formatted with fmt.
+var catalog = map[string]map[Code]string{
+	"pt-BR": {
+		CodeInvalidID:               "ID deve ser um número entre 1 e %d ou o ID de uma forma alternativa",
+		CodeNameRequired:            "Nome do Pokémon é obrigatório",
+		CodeQueryRequired:           "Parâmetro 'q' é obrigatório",
+		CodeVersionGroupRequired:    "Parâmetro 'version_group' é obrigatório",
+		CodeUnsupportedLanguage:     "Idioma não suportado: %s",
+		CodeInvalidPage:             "Parâmetro 'page' deve ser um número maior que zero",
+		CodeInvalidLimit:            "Parâmetro 'limit' deve estar entre 1 e %d",
+		CodeInvalidGeneration:       "Geração inválida: %s",
+		CodeInvalidSort:             "Ordenação inválida: %s",
+		CodeInvalidType:             "Tipo inválido: %s",
+		CodeNameIndexUnavailable:    "Índice de nomes ainda não está disponível",
+		CodeIndexUnavailable:        "A listagem de Pokémon requer o armazenamento local",
+		CodeUnauthorized:            "Token de administração ausente ou inválido",
+		CodePokemonNotFound:         "Pokémon não encontrado",
+		CodeUpstreamTimeout:         "Tempo de resposta da PokeAPI excedido, tente novamente mais tarde",
+		CodeUpstreamRateLimited:     "Limite de requisições da PokeAPI excedido, tente novamente mais tarde",
+		CodeUpstreamUnavailable:     "PokeAPI indisponível no momento, tente novamente mais tarde",
+		CodeUpstreamInvalidResponse: "Resposta inválida da PokeAPI",
+		CodeMoveNotFound:            "Um golpe do Pokémon não foi encontrado na PokeAPI",
+		CodeInternalError:           "Erro interno ao buscar o Pokémon, tente novamente mais tarde",
+		CodeAPIHealthy:              "PokedexIA API está funcionando",
+	},
+	"en": {
+		CodeInvalidID:               "ID must be a number between 1 and %d or the ID of an alternate form",
+		CodeNameRequired:            "Pokémon name is required",
+		CodeQueryRequired:           "Parameter 'q' is required",
+		CodeVersionGroupRequired:    "Parameter 'version_group' is required",
+		CodeUnsupportedLanguage:     "Unsupported language: %s",
+		CodeInvalidPage:             "Parameter 'page' must be a number greater than zero",
+		CodeInvalidLimit:            "Parameter 'limit' must be between 1 and %d",
+		CodeInvalidGeneration:       "Invalid generation: %s",
+		CodeInvalidSort:             "Invalid sort: %s",
+		CodeInvalidType:             "Invalid type: %s",
+		CodeNameIndexUnavailable:    "The name index is not available yet",
+		CodeIndexUnavailable:        "Listing Pokémon requires the local store",
+		CodeUnauthorized:            "Missing or invalid admin token",
+		CodePokemonNotFound:         "Pokémon not found",
+		CodeUpstreamTimeout:         "PokeAPI took too long to respond, please try again later",
+		CodeUpstreamRateLimited:     "PokeAPI rate limit exceeded, please try again later",
+		CodeUpstreamUnavailable:     "PokeAPI is unavailable at the moment, please try again later",
+		CodeUpstreamInvalidResponse: "Invalid response from PokeAPI",
+		CodeMoveNotFound:            "A move of the Pokémon was not found in PokeAPI",
+		CodeInternalError:           "Internal error fetching the Pokémon, please try again later",
+		CodeAPIHealthy:              "PokedexIA API is running",
+	},
+	"es": {
+		CodeInvalidID:               "El ID debe ser un número entre 1 y %d o el ID de una forma alternativa",
+		CodeNameRequired:            "El nombre del Pokémon es obligatorio",
+		CodeQueryRequired:           "El parámetro 'q' es obligatorio",
+		CodeVersionGroupRequired:    "El parámetro 'version_group' es obligatorio",
+		CodeUnsupportedLanguage:     "Idioma no soportado: %s",
+		CodeInvalidPage:             "El parámetro 'page' debe ser un número mayor que cero",
+		CodeInvalidLimit:            "El parámetro 'limit' debe estar entre 1 y %d",
+		CodeInvalidGeneration:       "Generación inválida: %s",
+		CodeInvalidSort:             "Orden inválido: %s",
+		CodeInvalidType:             "Tipo inválido: %s",
+		CodeNameIndexUnavailable:    "El índice de nombres aún no está disponible",
+		CodeIndexUnavailable:        "El listado de Pokémon requiere el almacenamiento local",
+		CodeUnauthorized:            "Token de administración ausente o inválido",
+		CodePokemonNotFound:         "Pokémon no encontrado",
+		CodeUpstreamTimeout:         "La PokeAPI tardó demasiado en responder, inténtalo de nuevo más tarde",
+		CodeUpstreamRateLimited:     "Límite de solicitudes de la PokeAPI excedido, inténtalo de nuevo más tarde",
+		CodeUpstreamUnavailable:     "La PokeAPI no está disponible en este momento, inténtalo de nuevo más tarde",
+		CodeUpstreamInvalidResponse: "Respuesta inválida de la PokeAPI",
+		CodeMoveNotFound:            "Un movimiento del Pokémon no se encontró en la PokeAPI",
+		CodeInternalError:           "Error interno al buscar el Pokémon, inténtalo de nuevo más tarde",
+		CodeAPIHealthy:              "PokedexIA API está funcionando",
+	},
+}
+
+// titles holds a short English summary of each error code, which does not
+// change from one occurrence of the error to another
+var titles = map[Code]string{
+	CodeInvalidID:               "Invalid Pokémon ID",
+	CodeNameRequired:            "Missing Pokémon name",
+	CodeQueryRequired:           "Missing search query",
+	CodeVersionGroupRequired:    "Missing version group",
+	CodeUnsupportedLanguage:     "Unsupported language",
+	CodeInvalidPage:             "Invalid page",
+	CodeInvalidLimit:            "Invalid limit",
+	CodeInvalidGeneration:       "Invalid generation",
+	CodeInvalidSort:             "Invalid sort",
+	CodeInvalidType:             "Invalid type",
+	CodeNameIndexUnavailable:    "Name index unavailable",
+	CodeIndexUnavailable:        "Pokémon index unavailable",
+	CodeUnauthorized:            "Unauthorized",
+	CodePokemonNotFound:         "Pokémon not found",
+	CodeUpstreamTimeout:         "PokeAPI timeout",
+	CodeUpstreamRateLimited:     "PokeAPI rate limit exceeded",
+	CodeUpstreamUnavailable:     "PokeAPI unavailable",
+	CodeUpstreamInvalidResponse: "Invalid PokeAPI response",
+	CodeMoveNotFound:            "Move not found",
+	CodeInternalError:           "Internal error",
+}
+
+// Title returns the short summary of an error code
+func Title(code Code) string {
+	if title, ok := titles[code]; ok {
+		return title
+	}
+	return string(code)
+}
+
+// Message returns the message of the code in a PokeAPI language. Without a
+// language the message is in DefaultMessages; languages without a translation
+// get English.
+func Message(lang string, code Code, args ...any) string {
+	messages, ok := catalog[lang]
+	if !ok {
+		messages = catalog[Default]
+		if lang == "" {
+			messages = catalog[DefaultMessages]
+		}
+	}
+
+	format, ok := messages[code]
+	if !ok {
+		return string(code)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// NegotiateMessages returns the language with translated messages the client
+// prefers most according to an Accept-Language header, or an empty string if
+// there is none
+func NegotiateMessages(acceptLanguage string) string {
+	return negotiate(acceptLanguage, func(code string) bool {
+		_, ok := catalog[code]
+		return ok
+	})
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import "testing"
+
+func TestCatalog_Complete(t *testing.T) {
+	for code := range catalog[DefaultMessages] {
+		for lang, messages := range catalog {
+			if _, ok := messages[code]; !ok {
+				t.Errorf("Missing %s message in %s", code, lang)
+			}
+		}
+	}
+}
+
+func TestTitles_Complete(t *testing.T) {
+	for code := range catalog[DefaultMessages] {
+		if code == CodeAPIHealthy {
+			continue
+		}
+		if _, ok := titles[code]; !ok {
+			t.Errorf("Missing title of %s", code)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	if got := Message("en", CodeInvalidLimit, 100); got != "Parameter 'limit' must be between 1 and 100" {
+		t.Errorf("Unexpected message %q", got)
+	}
+
+	// No language: the default catalog
+	if got, expected := Message("", CodePokemonNotFound), catalog[DefaultMessages][CodePokemonNotFound]; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	// No translation: English
+	if got, expected := Message("ja", CodePokemonNotFound), catalog[Default][CodePokemonNotFound]; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+
+	// Unknown codes are returned as is
+	if got := Message("en", Code("SOMETHING_ELSE")); got != "SOMETHING_ELSE" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
+
+func TestNegotiateMessages(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"ja":                     "",
+		"ja, es;q=0.8, en;q=0.5": "es",
+		"pt-PT":                  "pt-BR",
+	}
+
+	for header, expected := range tests {
+		if got := NegotiateMessages(header); got != expected {
+			t.Errorf("NegotiateMessages(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package index keeps an in-memory index of Pokémon summaries that can be
+// filtered, sorted and paginated without calling PokeAPI.
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/store"
+	"pokedexia-backend/internal/typechart"
+	"pokedexia-backend/internal/types"
+)
+
+// Errors returned for invalid queries. ErrUnknownSortField and ErrUnknownType
+// also match ErrInvalidQuery.
+var (
+	ErrInvalidQuery     = errors.New("invalid query")
+	ErrUnknownSortField = fmt.Errorf("%w: unknown sort field", ErrInvalidQuery)
+	ErrUnknownType      = fmt.Errorf("%w: unknown type", ErrInvalidQuery)
+)
+
+// Entry represents an indexed Pokémon
+type Entry struct {
+	Pokemon    types.PokemonResponse
+	Generation int
+}
+
+// Query represents the filters, order and page of a listing. Zero values
+// disable the filters and sort by ID.
+type Query struct {
+	Type       string
+	Generation int
+	SortField  string
+	Descending bool
+	Offset     int
+	Limit      int
+}
+
+// Page represents a page of results and how many entries matched the filters
+type Page struct {
+	Pokemon []types.PokemonResponse
+	Total   int
+}
+
+// sortKeys extracts the value each sortable field is ordered by
+var sortKeys = map[string]func(p *types.PokemonResponse) int{
+	"id":              func(p *types.PokemonResponse) int { return p.ID },
+	"hp":              func(p *types.PokemonResponse) int { return p.Stats.HP },
+	"attack":          func(p *types.PokemonResponse) int { return p.Stats.Attack },
+	"defense":         func(p *types.PokemonResponse) int { return p.Stats.Defense },
+	"special_attack":  func(p *types.PokemonResponse) int { return p.Stats.SpecialAttack },
+	"special_defense": func(p *types.PokemonResponse) int { return p.Stats.SpecialDefense },
+	"speed":           func(p *types.PokemonResponse) int { return p.Stats.Speed },
+	"total":           statTotal,
+	"height":          func(p *types.PokemonResponse) int { return p.Height },
+	"weight":          func(p *types.PokemonResponse) int { return p.Weight },
+}
+
+// romanGenerations maps the roman numerals PokeAPI uses in generation names
+var romanGenerations = map[string]int{
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5, "vi": 6, "vii": 7, "viii": 8, "ix": 9,
+}
+
+// Index represents the in-memory index, safe for concurrent use
+type Index struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// New creates an empty index
+func New() *Index {
+	return &Index{}
+}
+
+// Add indexes a Pokémon, replacing any previous entry with the same ID
+func (ix *Index) Add(entry Entry) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	i := sort.Search(len(ix.entries), func(i int) bool {
+		return ix.entries[i].Pokemon.ID >= entry.Pokemon.ID
+	})
+	if i < len(ix.entries) && ix.entries[i].Pokemon.ID == entry.Pokemon.ID {
+		ix.entries[i] = entry
+		return
+	}
+
+	ix.entries = append(ix.entries, Entry{})
+	copy(ix.entries[i+1:], ix.entries[i:])
+	ix.entries[i] = entry
+}
+
+// Has reports whether the Pokémon with the ID is indexed
+func (ix *Index) Has(id int) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	i := sort.Search(len(ix.entries), func(i int) bool {
+		return ix.entries[i].Pokemon.ID >= id
+	})
+	return i < len(ix.entries) && ix.entries[i].Pokemon.ID == id
+}
+
+// Len returns the number of indexed Pokémon
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.entries)
+}
+
+// Query returns the page of Pokémon matching the query
+func (ix *Index) Query(q Query) (*Page, error) {
+	key := sortKeys["id"]
+	if q.SortField != "" {
+		var ok bool
+		if key, ok = sortKeys[q.SortField]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownSortField, q.SortField)
+		}
+	}
+	if q.Type != "" && !typechart.Valid(q.Type) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, q.Type)
+	}
+
+	ix.mu.RLock()
+	matches := make([]*types.PokemonResponse, 0, len(ix.entries))
+	for i := range ix.entries {
+		entry := &ix.entries[i]
+		if q.Generation != 0 && entry.Generation != q.Generation {
+			continue
+		}
+		if q.Type != "" && !hasType(&entry.Pokemon, q.Type) {
+			continue
+		}
+		matches = append(matches, &entry.Pokemon)
+	}
+	ix.mu.RUnlock()
+
+	// Entries are kept by ID, so a stable sort breaks ties by ID
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := key(matches[i]), key(matches[j])
+		if q.Descending {
+			return a > b
+		}
+		return a < b
+	})
+
+	page := &Page{Pokemon: []types.PokemonResponse{}, Total: len(matches)}
+	offset := min(max(q.Offset, 0), len(matches))
+
+	end := len(matches)
+	if q.Limit > 0 && q.Limit < end-offset {
+		end = offset + q.Limit
+	}
+	for _, p := range matches[offset:end] {
+		page.Pokemon = append(page.Pokemon, *p)
+	}
+	return page, nil
+}
+
+// Load indexes the Pokémon with the IDs that are in the local store,
+// skipping the ones that were never fetched. It returns how many were indexed.
+func (ix *Index) Load(st store.Store, ids []int) (int, error) {
+	loaded := 0
+	for _, id := range ids {
+		body, err := st.Get(fmt.Sprintf("pokemon/%d", id))
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return loaded, err
+		}
+
+		var pokemon types.Pokemon
+		if err := json.Unmarshal(body, &pokemon); err != nil {
+			return loaded, fmt.Errorf("pokemon %d: %w", id, err)
+		}
+
+		entry := Entry{Pokemon: *services.TransformPokemonToResponse(&pokemon)}
+
+		speciesID := services.ResourceID(pokemon.Species.URL)
+		if speciesID == 0 {
+			speciesID = id
+		}
+		if body, err := st.Get(fmt.Sprintf("pokemon-species/%d", speciesID)); err == nil {
+			var species types.PokemonSpecies
+			if err := json.Unmarshal(body, &species); err != nil {
+				return loaded, fmt.Errorf("species %d: %w", speciesID, err)
+			}
+			entry.Generation, _ = ParseGeneration(species.Generation.Name)
+		}
+
+		ix.Add(entry)
+		loaded++
+	}
+	return loaded, nil
+}
+
+// ParseGeneration parses a generation given as a number ("4"), a roman
+// numeral ("iv") or a PokeAPI name ("generation-iv")
+func ParseGeneration(s string) (int, error) {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "generation-")
+
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(romanGenerations) {
+		return n, nil
+	}
+	if n, ok := romanGenerations[s]; ok {
+		return n, nil
+	}
+	return 0, fmt.Errorf("%w: unknown generation %q", ErrInvalidQuery, s)
+}
+
+// SortFields returns the fields listings can be sorted by
+func SortFields() []string {
+	fields := make([]string, 0, len(sortKeys))
+	for field := range sortKeys {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// hasType reports whether the Pokémon has the type
+func hasType(p *types.PokemonResponse, name string) bool {
+	for _, t := range p.Types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// statTotal returns the sum of the base stats
+func statTotal(p *types.PokemonResponse) int {
+	s := p.Stats
+	return s.HP + s.Attack + s.Defense + s.SpecialAttack + s.SpecialDefense + s.Speed
+}
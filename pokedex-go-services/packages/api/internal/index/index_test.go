@@ -0,0 +1,173 @@
+package index
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"pokedexia-backend/internal/store"
+	"pokedexia-backend/internal/types"
+)
+
+func newEntry(id int, name string, generation, attack int, pokemonTypes ...string) Entry {
+	return Entry{
+		Pokemon: types.PokemonResponse{
+			ID:    id,
+			Name:  name,
+			Types: pokemonTypes,
+			Stats: types.Stats{Attack: attack},
+		},
+		Generation: generation,
+	}
+}
+
+func newTestIndex() *Index {
+	ix := New()
+	// Added out of order on purpose
+	ix.Add(newEntry(6, "charizard", 1, 84, "fire", "flying"))
+	ix.Add(newEntry(4, "charmander", 1, 52, "fire"))
+	ix.Add(newEntry(155, "cyndaquil", 2, 52, "fire"))
+	ix.Add(newEntry(1, "bulbasaur", 1, 49, "grass", "poison"))
+	ix.Add(newEntry(25, "pikachu", 1, 55, "electric"))
+	return ix
+}
+
+func names(page *Page) []string {
+	var result []string
+	for _, p := range page.Pokemon {
+		result = append(result, p.Name)
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndex_Query(t *testing.T) {
+	ix := newTestIndex()
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []string
+		total    int
+	}{
+		{"default order is by ID", Query{}, []string{"bulbasaur", "charmander", "charizard", "pikachu", "cyndaquil"}, 5},
+		{"filter by type", Query{Type: "fire"}, []string{"charmander", "charizard", "cyndaquil"}, 3},
+		{"filter by generation", Query{Type: "fire", Generation: 2}, []string{"cyndaquil"}, 1},
+		{"sort descending", Query{SortField: "attack", Descending: true}, []string{"charizard", "pikachu", "charmander", "cyndaquil", "bulbasaur"}, 5},
+		{"ties keep ID order", Query{Type: "fire", SortField: "attack"}, []string{"charmander", "cyndaquil", "charizard"}, 3},
+		{"paginate", Query{Offset: 2, Limit: 2}, []string{"charizard", "pikachu"}, 5},
+		{"offset past the end", Query{Offset: 10, Limit: 2}, nil, 5},
+		{"negative offset", Query{Offset: -4, Limit: 2}, []string{"bulbasaur", "charmander"}, 5},
+	}
+
+	for _, tt := range tests {
+		page, err := ix.Query(tt.query)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		if got := names(page); !equal(got, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, got)
+		}
+		if page.Total != tt.total {
+			t.Errorf("%s: expected total %d, got %d", tt.name, tt.total, page.Total)
+		}
+	}
+}
+
+func TestIndex_InvalidQuery(t *testing.T) {
+	ix := newTestIndex()
+
+	for _, q := range []Query{{SortField: "cuteness"}, {Type: "sound"}} {
+		if _, err := ix.Query(q); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for %+v, got %v", q, err)
+		}
+	}
+}
+
+func TestIndex_AddReplaces(t *testing.T) {
+	ix := newTestIndex()
+	ix.Add(newEntry(25, "pikachu", 1, 90, "electric"))
+
+	if ix.Len() != 5 {
+		t.Errorf("Expected 5 entries, got %d", ix.Len())
+	}
+
+	page, _ := ix.Query(Query{SortField: "attack", Descending: true, Limit: 1})
+	if page.Pokemon[0].Name != "pikachu" {
+		t.Errorf("Expected the updated entry, got %v", names(page))
+	}
+}
+
+func TestIndex_Has(t *testing.T) {
+	ix := newTestIndex()
+
+	for id, expected := range map[int]bool{1: true, 25: true, 155: true, 2: false, 200: false} {
+		if ix.Has(id) != expected {
+			t.Errorf("Has(%d) = %v, expected %v", id, !expected, expected)
+		}
+	}
+}
+
+func TestIndex_Load(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "pokeapi.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer st.Close()
+
+	st.Put("pokemon/1", []byte(`{"id": 1, "name": "bulbasaur", "types": [{"slot": 1, "type": {"name": "grass"}}],
+		"species": {"name": "bulbasaur", "url": "https://pokeapi.co/api/v2/pokemon-species/1/"}}`))
+	st.Put("pokemon-species/1", []byte(`{"id": 1, "name": "bulbasaur", "generation": {"name": "generation-i"}}`))
+	// Species not synced: indexed without a generation
+	st.Put("pokemon/152", []byte(`{"id": 152, "name": "chikorita"}`))
+	// Alternate forms have the generation of their species
+	st.Put("pokemon/10001", []byte(`{"id": 10001, "name": "deoxys-attack",
+		"species": {"name": "deoxys", "url": "https://pokeapi.co/api/v2/pokemon-species/386/"}}`))
+	st.Put("pokemon-species/386", []byte(`{"id": 386, "name": "deoxys", "generation": {"name": "generation-iii"}}`))
+
+	ix := New()
+	loaded, err := ix.Load(st, []int{1, 2, 152, 10001})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loaded != 3 {
+		t.Fatalf("Expected 3 indexed Pokémon, got %d", loaded)
+	}
+
+	if page, _ := ix.Query(Query{Generation: 3}); !equal(names(page), []string{"deoxys-attack"}) {
+		t.Errorf("Expected the form in generation 3, got %v", names(page))
+	}
+
+	page, _ := ix.Query(Query{Generation: 1})
+	if !equal(names(page), []string{"bulbasaur"}) {
+		t.Errorf("Expected bulbasaur in generation 1, got %v", names(page))
+	}
+	if page.Pokemon[0].Types[0] != "grass" {
+		t.Errorf("Expected transformed summary, got %+v", page.Pokemon[0])
+	}
+}
+
+func TestParseGeneration(t *testing.T) {
+	for _, s := range []string{"4", "iv", "IV", "generation-iv"} {
+		if n, err := ParseGeneration(s); err != nil || n != 4 {
+			t.Errorf("ParseGeneration(%q) = %d, %v, expected 4", s, n, err)
+		}
+	}
+
+	for _, s := range []string{"0", "10", "x", "generation-"} {
+		if _, err := ParseGeneration(s); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("Expected ErrInvalidQuery for %q, got %v", s, err)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package index
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/store"
+	"pokedexia-backend/internal/types"
+)
+
+// foldings replaces the characters that users type in many ways
+var foldings = strings.NewReplacer(
+	"♀", "f", "♂", "m",
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// nameEntry represents an indexed name
+type nameEntry struct {
+	key    string
+	tokens []string
+	match  types.PokemonName
+}
+
+// NameIndex matches Pokémon names regardless of case, punctuation, spaces and
+// gender symbols, so "Mr. Mime", "mr mime" and "mr-mime" are the same name.
+// It is safe for concurrent use.
+type NameIndex struct {
+	mu      sync.RWMutex
+	entries []nameEntry
+	byKey   map[string]types.PokemonName
+}
+
+// NewNameIndex creates an empty name index
+func NewNameIndex() *NameIndex {
+	return &NameIndex{
+		byKey: make(map[string]types.PokemonName),
+	}
+}
+
+// Normalize reduces a name to lowercase letters and digits, folding accents
+// and turning gender symbols into "f" and "m"
+func Normalize(name string) string {
+	name = foldings.Replace(strings.ToLower(name))
+
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Add indexes a name of the species with the given ID in a PokeAPI language.
+// When two species share a normalized name, the first one added keeps it.
+func (n *NameIndex) Add(id int, name, language string) {
+	key := Normalize(name)
+	if key == "" {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.byKey[key]; ok {
+		return
+	}
+
+	match := types.PokemonName{ID: id, Name: name, Language: language}
+	n.byKey[key] = match
+
+	var tokens []string
+	for _, token := range strings.FieldsFunc(strings.ToLower(name), isSeparator) {
+		if t := Normalize(token); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	n.entries = append(n.entries, nameEntry{key: key, tokens: tokens, match: match})
+}
+
+// AddSpecies indexes the species name in every language PokeAPI has
+func (n *NameIndex) AddSpecies(species *types.PokemonSpecies) {
+	n.Add(species.ID, species.Name, i18n.Default)
+	for _, name := range species.Names {
+		n.Add(species.ID, name.Name, name.Language.Name)
+	}
+}
+
+// LoadSpecies indexes the localized names of every species in the local store
+// with an ID up to maxID. It returns how many species were indexed.
+func (n *NameIndex) LoadSpecies(st store.Store, maxID int) (int, error) {
+	loaded := 0
+	for id := 1; id <= maxID; id++ {
+		body, err := st.Get(fmt.Sprintf("pokemon-species/%d", id))
+		if errors.Is(err, store.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return loaded, err
+		}
+
+		var species types.PokemonSpecies
+		if err := json.Unmarshal(body, &species); err != nil {
+			return loaded, fmt.Errorf("species %d: %w", id, err)
+		}
+
+		n.AddSpecies(&species)
+		loaded++
+	}
+	return loaded, nil
+}
+
+// Len returns the number of indexed names
+func (n *NameIndex) Len() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return len(n.entries)
+}
+
+// Lookup returns the species whose name matches the query exactly, after normalization
+func (n *NameIndex) Lookup(query string) (types.PokemonName, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	match, ok := n.byKey[Normalize(query)]
+	return match, ok
+}
+
+// Prefix returns up to limit species whose name, or a word of it, starts with
+// the query, ordered by ID
+func (n *NameIndex) Prefix(query string, limit int) []types.PokemonName {
+	prefix := Normalize(query)
+	matches := []types.PokemonName{}
+	if prefix == "" {
+		return matches
+	}
+
+	n.mu.RLock()
+	for _, entry := range n.entries {
+		if strings.HasPrefix(entry.key, prefix) || hasTokenPrefix(entry.tokens, prefix) {
+			matches = append(matches, entry.match)
+		}
+	}
+	n.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].ID < matches[j].ID
+	})
+	return truncate(uniqueIDs(matches), limit)
+}
+
+// Suggest returns up to limit species with names close to the query, for
+// "did you mean" hints. Closer names come first.
+func (n *NameIndex) Suggest(query string, limit int) []types.PokemonName {
+	key := Normalize(query)
+	suggestions := []types.PokemonName{}
+	if key == "" {
+		return suggestions
+	}
+
+	type scored struct {
+		match    types.PokemonName
+		distance int
+	}
+	var candidates []scored
+
+	maxDistance := maxEdits(key)
+
+	n.mu.RLock()
+	for _, entry := range n.entries {
+		distance := levenshtein(key, entry.key)
+		// Typing part of a long name should still suggest it
+		if strings.HasPrefix(entry.key, key) && len(key) >= 3 {
+			distance = min(distance, 1)
+		}
+		if distance <= maxDistance {
+			candidates = append(candidates, scored{entry.match, distance})
+		}
+	}
+	n.mu.RUnlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].match.ID < candidates[j].match.ID
+	})
+
+	for _, c := range candidates {
+		suggestions = append(suggestions, c.match)
+	}
+	return truncate(uniqueIDs(suggestions), limit)
+}
+
+// maxEdits returns how many typos are tolerated for a query of this length
+func maxEdits(key string) int {
+	switch n := len([]rune(key)); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// isSeparator reports whether the rune separates words in a name
+func isSeparator(r rune) bool {
+	return r == '-' || r == ' ' || r == '_' || r == '.'
+}
+
+// hasTokenPrefix reports whether any word starts with the prefix
+func hasTokenPrefix(tokens []string, prefix string) bool {
+	for _, token := range tokens {
+		if strings.HasPrefix(token, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueIDs keeps the first match of each species, as a species is indexed
+// once per language
+func uniqueIDs(matches []types.PokemonName) []types.PokemonName {
+	seen := make(map[int]bool, len(matches))
+	unique := matches[:0]
+	for _, m := range matches {
+		if !seen[m.ID] {
+			seen[m.ID] = true
+			unique = append(unique, m)
+		}
+	}
+	return unique
+}
+
+// truncate limits the matches to limit entries, or all of them if limit is not positive
+func truncate(matches []types.PokemonName, limit int) []types.PokemonName {
+	if limit > 0 && len(matches) > limit {
+		return matches[:limit]
+	}
+	return matches
+}
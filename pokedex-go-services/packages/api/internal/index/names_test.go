@@ -0,0 +1,186 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pokedexia-backend/internal/store"
+	"pokedexia-backend/internal/types"
+)
+
+func newTestNameIndex() *NameIndex {
+	names := NewNameIndex()
+	for id, name := range map[int]string{
+		25:  "pikachu",
+		29:  "nidoran-f",
+		32:  "nidoran-m",
+		83:  "farfetchd",
+		122: "mr-mime",
+		439: "mime-jr",
+		669: "flabébé",
+		731: "pikipek",
+		772: "type-null",
+	} {
+		names.Add(id, name, "en")
+	}
+	return names
+}
+
+func ids(matches []types.PokemonName) []int {
+	var result []int
+	for _, m := range matches {
+		result = append(result, m.ID)
+	}
+	return result
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Mr. Mime":   "mrmime",
+		"mr-mime":    "mrmime",
+		"Farfetch'd": "farfetchd",
+		"Nidoran♀":   "nidoranf",
+		"nidoran f":  "nidoranf",
+		"Type: Null": "typenull",
+		"Flabébé":    "flabebe",
+		"Porygon-Z":  "porygonz",
+		"  ":         "",
+	}
+
+	for input, expected := range tests {
+		if got := Normalize(input); got != expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestNameIndex_Lookup(t *testing.T) {
+	names := newTestNameIndex()
+
+	tests := map[string]int{
+		"Mr Mime":    122,
+		"farfetch'd": 83,
+		"nidoran f":  29,
+		"Nidoran♂":   32,
+		"TYPE: NULL": 772,
+		"flabebe":    669,
+	}
+
+	for query, expected := range tests {
+		match, ok := names.Lookup(query)
+		if !ok || match.ID != expected {
+			t.Errorf("Lookup(%q) = %+v, %v, expected ID %d", query, match, ok, expected)
+		}
+	}
+
+	if _, ok := names.Lookup("pikach"); ok {
+		t.Error("Expected no exact match for a partial name")
+	}
+}
+
+func TestNameIndex_Prefix(t *testing.T) {
+	names := newTestNameIndex()
+
+	if got := ids(names.Prefix("pik", 10)); !equalInts(got, []int{25, 731}) {
+		t.Errorf("Expected pikachu and pikipek, got %v", got)
+	}
+
+	// Words inside a name also match
+	if got := ids(names.Prefix("mime", 10)); !equalInts(got, []int{122, 439}) {
+		t.Errorf("Expected mr-mime and mime-jr, got %v", got)
+	}
+
+	if got := ids(names.Prefix("nidoran", 1)); !equalInts(got, []int{29}) {
+		t.Errorf("Expected the limit to apply, got %v", got)
+	}
+
+	if got := names.Prefix("!!", 10); len(got) != 0 {
+		t.Errorf("Expected no matches, got %v", got)
+	}
+}
+
+func TestNameIndex_Suggest(t *testing.T) {
+	names := newTestNameIndex()
+
+	tests := map[string]int{
+		"pikach":   25,
+		"picachu":  25,
+		"farfetch": 83,
+		"mr mine":  122,
+	}
+
+	for query, expected := range tests {
+		suggestions := names.Suggest(query, 3)
+		if len(suggestions) == 0 || suggestions[0].ID != expected {
+			t.Errorf("Suggest(%q) = %v, expected %d first", query, suggestions, expected)
+		}
+	}
+
+	if got := names.Suggest("charizard", 3); len(got) != 0 {
+		t.Errorf("Expected no suggestions for an unrelated name, got %v", got)
+	}
+}
+
+func TestNameIndex_LoadSpecies(t *testing.T) {
+	st, err := store.Open(filepath.Join(t.TempDir(), "pokeapi.db"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer st.Close()
+
+	st.Put("pokemon-species/4", []byte(`{"id": 4, "name": "charmander", "names": [
+		{"name": "Charmander", "language": {"name": "en"}},
+		{"name": "Salamèche", "language": {"name": "fr"}},
+		{"name": "Glumanda", "language": {"name": "de"}},
+		{"name": "ヒトカゲ", "language": {"name": "ja-Hrkt"}}
+	]}`))
+
+	names := NewNameIndex()
+	loaded, err := names.LoadSpecies(st, 10)
+	if err != nil || loaded != 1 {
+		t.Fatalf("Expected 1 species, got %d, %v", loaded, err)
+	}
+
+	for query, language := range map[string]string{"salameche": "fr", "Glumanda": "de", "ヒトカゲ": "ja-Hrkt", "charmander": "en"} {
+		match, ok := names.Lookup(query)
+		if !ok || match.ID != 4 || match.Language != language {
+			t.Errorf("Lookup(%q) = %+v, %v, expected charmander in %s", query, match, ok, language)
+		}
+	}
+
+	// Each species is listed once even when several of its names match
+	names.Add(4, "Charmander-X", "roomaji")
+	if got := names.Prefix("charm", 10); len(got) != 1 {
+		t.Errorf("Expected a single match, got %v", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "abc", 3},
+		{"pikachu", "pikachu", 0},
+		{"pikachu", "picachu", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+// Package middleware holds the Gin middleware shared by every route.
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"pokedexia-backend/internal/origin"
+)
+
+// RequestIDHeader is the header that carries the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the Gin context key of the request ID
+const requestIDKey = "request_id"
+
+// maxRequestIDLength limits the IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID propagates the X-Request-ID header of the request, or generates a
+// new ID when it is missing or invalid, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// TrackOrigin records where the data of each request comes from, so
+// responses can report whether they were cached
+func TrackOrigin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, _ := origin.With(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request, or an empty string when the
+// RequestID middleware is not in use
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// validRequestID reports whether a client-provided ID is safe to reuse
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random 128-bit ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("middleware: reading random bytes: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
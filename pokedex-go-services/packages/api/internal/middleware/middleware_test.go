@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"pokedexia-backend/internal/origin"
+)
+
+func setupTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), TrackOrigin())
+	router.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, GetRequestID(c))
+	})
+	return router
+}
+
+func TestRequestID(t *testing.T) {
+	router := setupTestRouter()
+
+	testCases := []struct {
+		name      string
+		header    string
+		propagate bool
+	}{
+		{"missing", "", false},
+		{"valid", "abc-123", true},
+		{"with spaces", "abc 123", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			router.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, id, w.Body.String())
+			if tc.propagate {
+				assert.Equal(t, tc.header, id)
+			} else {
+				assert.Len(t, id, 32)
+			}
+		})
+	}
+}
+
+func TestRequestID_Unique(t *testing.T) {
+	router := setupTestRouter()
+
+	ids := make(map[string]bool)
+	for i := 0; i < 10; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		router.ServeHTTP(w, req)
+		ids[w.Header().Get(RequestIDHeader)] = true
+	}
+
+	assert.Len(t, ids, 10)
+}
+
+func TestTrackOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TrackOrigin())
+
+	var tracked *origin.Origin
+	router.GET("/", func(c *gin.Context) {
+		tracked = origin.From(c.Request.Context())
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+
+	assert.NotNil(t, tracked)
+}
@@ -0,0 +1,87 @@
+// Package origin records where the data served to a request came from, so
+// sources can note it as they answer and responses can report it.
+package origin
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Sources data can be served from
+const (
+	SourcePokeAPI = "pokeapi"
+	SourceStore   = "store"
+	SourceCache   = "cache"
+	SourceMemory  = "memory"
+)
+
+// originKey is the context key of the request Origin
+type originKey struct{}
+
+// Origin records where the data served to a request came from. Sources add
+// to it as they answer; it is safe for concurrent use.
+type Origin struct {
+	mu      sync.Mutex
+	sources []string
+}
+
+// With returns a context that records the origin of the data fetched with it
+func With(ctx context.Context) (context.Context, *Origin) {
+	origin := &Origin{}
+	return context.WithValue(ctx, originKey{}, origin), origin
+}
+
+// From returns the origin recorded in the context, or nil if there is none
+func From(ctx context.Context) *Origin {
+	origin, _ := ctx.Value(originKey{}).(*Origin)
+	return origin
+}
+
+// Record notes that data was served from the source, if the context records origins
+func Record(ctx context.Context, source string) {
+	origin := From(ctx)
+	if origin == nil {
+		return
+	}
+
+	origin.mu.Lock()
+	defer origin.mu.Unlock()
+
+	for _, s := range origin.sources {
+		if s == source {
+			return
+		}
+	}
+	origin.sources = append(origin.sources, source)
+}
+
+// Source returns the sources that served data, comma-separated in the order they were first used
+func (o *Origin) Source() string {
+	if o == nil {
+		return ""
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return strings.Join(o.sources, ",")
+}
+
+// Cached reports whether all the data was served without calling the PokeAPI
+// or another live source
+func (o *Origin) Cached() bool {
+	if o == nil {
+		return false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, s := range o.sources {
+		if s != SourceCache && s != SourceStore {
+			return false
+		}
+	}
+	return len(o.sources) > 0
+}
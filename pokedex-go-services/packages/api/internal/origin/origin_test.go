@@ -0,0 +1,31 @@
+package origin
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOrigin_MixedSources(t *testing.T) {
+	ctx, origin := With(context.Background())
+
+	Record(ctx, SourceCache)
+	Record(ctx, SourcePokeAPI)
+	Record(ctx, SourceCache)
+
+	if origin.Source() != "cache,pokeapi" {
+		t.Errorf("expected source %q, got %q", "cache,pokeapi", origin.Source())
+	}
+	if origin.Cached() {
+		t.Error("expected data from the PokeAPI not to be cached")
+	}
+}
+
+func TestOrigin_Missing(t *testing.T) {
+	// Sources must work without an origin in the context
+	Record(context.Background(), SourceCache)
+
+	origin := From(context.Background())
+	if origin.Source() != "" || origin.Cached() {
+		t.Error("expected a missing origin to report nothing")
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"pokedexia-backend/internal/types"
+)
+
+// ErrCircuitOpen is the cause of the errors returned while the circuit breaker
+// rejects requests. It always comes wrapped with ErrUpstreamUnavailable.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState represents the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every request until the open timeout elapses
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe requests through
+	BreakerHalfOpen
+)
+
+// String returns the name of the state
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calling the PokeAPI after consecutive failures, so
+// requests fail fast instead of waiting for timeouts while it is down. After
+// the open timeout a few probe requests are allowed; one success closes the
+// circuit again and one failure reopens it.
+type CircuitBreaker struct {
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenRequests int
+	now              func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// NewCircuitBreaker creates a circuit breaker that opens after the given
+// number of consecutive failures
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration, halfOpenRequests int) *CircuitBreaker {
+	if halfOpenRequests < 1 {
+		halfOpenRequests = 1
+	}
+
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		halfOpenRequests: halfOpenRequests,
+		now:              time.Now,
+	}
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	return b.state
+}
+
+// Status returns the state of the breaker for the health check
+func (b *CircuitBreaker) Status() types.BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	status := types.BreakerStatus{
+		State:               b.state.String(),
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// allow returns ErrCircuitOpen if the request must not reach the PokeAPI
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.probes >= b.halfOpenRequests {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a request that was allowed
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrThrottled):
+		// The request never reached the PokeAPI or the callers gave up,
+		// which says nothing about its health
+	case errors.Is(err, ErrUpstreamUnavailable):
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.failureThreshold {
+			b.state = BreakerOpen
+			b.openedAt = b.now()
+			b.probes = 0
+		}
+	default:
+		// Any answer from the PokeAPI, including 404, means it is up
+		b.state = BreakerClosed
+		b.failures = 0
+		b.probes = 0
+	}
+}
+
+// advance moves an open breaker to half-open once the open timeout elapses.
+// The caller must hold the lock.
+func (b *CircuitBreaker) advance() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		b.state = BreakerHalfOpen
+		b.probes = 0
+	}
+}
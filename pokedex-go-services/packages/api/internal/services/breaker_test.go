@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/config"
+)
+
+var errUnavailable = &UpstreamError{Path: "/pokemon/25", StatusCode: 503, Kind: ErrUpstreamUnavailable}
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute, 1)
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("Expected closed breaker to allow requests, got %v", err)
+	}
+	breaker.record(errUnavailable)
+	if state := breaker.State(); state != BreakerClosed {
+		t.Fatalf("Expected closed after 1 failure, got %s", state)
+	}
+
+	breaker.allow()
+	breaker.record(errUnavailable)
+	if state := breaker.State(); state != BreakerOpen {
+		t.Fatalf("Expected open after 2 failures, got %s", state)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen, got %v", err)
+	}
+
+	// After the open timeout a single probe is allowed
+	now = now.Add(time.Minute)
+	if state := breaker.State(); state != BreakerHalfOpen {
+		t.Fatalf("Expected half-open after the timeout, got %s", state)
+	}
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("Expected the probe to be allowed, got %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected a second probe to be rejected, got %v", err)
+	}
+
+	// A failed probe reopens the circuit
+	breaker.record(errUnavailable)
+	if state := breaker.State(); state != BreakerOpen {
+		t.Fatalf("Expected open after a failed probe, got %s", state)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	breaker.allow()
+	breaker.record(nil)
+	if state := breaker.State(); state != BreakerClosed {
+		t.Fatalf("Expected closed after a successful probe, got %s", state)
+	}
+	if status := breaker.Status(); status.ConsecutiveFailures != 0 || status.OpenedAt != nil {
+		t.Errorf("Expected a reset status, got %+v", status)
+	}
+}
+
+func TestCircuitBreaker_IgnoresNonUpstreamErrors(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Minute, 1)
+
+	breaker.record(&UpstreamError{Path: "/pokemon/0", StatusCode: 404, Kind: ErrNotFound})
+	breaker.record(context.Canceled)
+
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("Expected closed breaker, got %s", state)
+	}
+}
+
+func newBreakerConfig(url string) *config.Config {
+	return &config.Config{
+		PokeAPIBaseURL:          url,
+		BreakerFailureThreshold: 2,
+		BreakerOpenTimeout:      time.Minute,
+		BreakerHalfOpenRequests: 1,
+	}
+}
+
+func TestPokeAPIService_CircuitBreakerFailsFast(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service := NewPokeAPIService(newBreakerConfig(server.URL))
+
+	for i := 0; i < 2; i++ {
+		service.GetPokemonByID(context.Background(), 25)
+	}
+
+	_, err := service.GetPokemonByID(context.Background(), 25)
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrCircuitOpen wrapped as ErrUpstreamUnavailable, got %v", err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected 2 upstream requests, got %d", got)
+	}
+
+	health := service.UpstreamHealth()
+	if health.CircuitBreaker == nil || health.CircuitBreaker.State != "open" {
+		t.Errorf("Expected an open breaker in the health report, got %+v", health.CircuitBreaker)
+	}
+}
+
+func TestPokeAPIService_FallsBackToStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	st := newMapStore()
+	st.Put("pokemon/25", []byte(`{"id": 25, "name": "pikachu"}`))
+
+	service := NewPokeAPIService(newBreakerConfig(server.URL)).WithStore(st)
+
+	// Served from the store both while the breaker is closed and once it is open
+	for i := 0; i < 4; i++ {
+		pokemon, err := service.GetPokemonByID(context.Background(), 25)
+		if err != nil {
+			t.Fatalf("Expected fallback to the store, got %v", err)
+		}
+		if pokemon.Name != "pikachu" {
+			t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+		}
+	}
+
+	if state := service.breaker.State(); state != BreakerOpen {
+		t.Errorf("Expected open breaker, got %s", state)
+	}
+
+	if _, err := service.GetPokemonByID(context.Background(), 1); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable without stored data, got %v", err)
+	}
+}
+
+func TestPokeAPIService_BreakerDisabled(t *testing.T) {
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: "http://localhost"})
+
+	if service.breaker != nil {
+		t.Error("Expected no circuit breaker with a zero threshold")
+	}
+	if health := service.UpstreamHealth(); health.CircuitBreaker != nil {
+		t.Errorf("Expected no breaker in the health report, got %+v", health.CircuitBreaker)
+	}
+}
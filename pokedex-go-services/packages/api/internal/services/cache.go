@@ -0,0 +1,398 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"pokedexia-backend/internal/origin"
+	"pokedexia-backend/internal/types"
+)
+
+// CacheManager represents a cache that can be inspected and purged
+type CacheManager interface {
+	Stats() types.CacheStats
+	Purge() int
+}
+
+// CachedSource is a PokemonSource decorator that keeps recently used Pokémon
+// in memory. Entries expire after the TTL and the least recently used entry is
+// evicted once the cache is full. Every entry is indexed by ID and by name, so
+// a lookup by name also serves later lookups by ID and vice versa. Expired
+// entries are kept until evicted and served while the upstream is unavailable.
+// Species, evolution chains and moves are cached the same way, each kind in
+// its own cache of the same capacity.
+type CachedSource struct {
+	source   PokemonSource
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	lru    *list.List
+	byID   map[int]*list.Element
+	byName map[string]*list.Element
+
+	species *resourceCache[*types.PokemonSpecies]
+	chains  *resourceCache[*types.EvolutionChain]
+	moves   *resourceCache[*types.Move]
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	stale  atomic.Uint64
+}
+
+// cacheEntry represents a cached Pokémon and every name it was requested by
+type cacheEntry struct {
+	pokemon   *types.Pokemon
+	names     []string
+	expiresAt time.Time
+}
+
+// Ensure the cached source satisfies the interfaces
+var (
+	_ PokemonSource  = (*CachedSource)(nil)
+	_ CacheManager   = (*CachedSource)(nil)
+	_ HealthReporter = (*CachedSource)(nil)
+)
+
+// NewCachedSource creates a cache in front of the given source. A TTL of zero
+// or less keeps entries until they are evicted.
+func NewCachedSource(source PokemonSource, capacity int, ttl time.Duration) *CachedSource {
+	return &CachedSource{
+		source:   source,
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		lru:      list.New(),
+		byID:     make(map[int]*list.Element),
+		byName:   make(map[string]*list.Element),
+		species:  newResourceCache[*types.PokemonSpecies](capacity),
+		chains:   newResourceCache[*types.EvolutionChain](capacity),
+		moves:    newResourceCache[*types.Move](capacity),
+	}
+}
+
+// GetPokemonByID searches for a Pokémon by ID, using the cache when possible
+func (c *CachedSource) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	c.mu.Lock()
+	cached, fresh := c.lookup(c.byID[id])
+	c.mu.Unlock()
+	if fresh {
+		origin.Record(ctx, origin.SourceCache)
+		return cached, nil
+	}
+
+	pokemon, err := c.source.GetPokemonByID(ctx, id)
+	if err != nil {
+		return c.fallback(ctx, cached, err)
+	}
+
+	c.store(pokemon)
+	return pokemon, nil
+}
+
+// GetPokemonByName searches for a Pokémon by name, using the cache when possible
+func (c *CachedSource) GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	key := normalizeName(name)
+
+	c.mu.Lock()
+	cached, fresh := c.lookup(c.byName[key])
+	c.mu.Unlock()
+	if fresh {
+		origin.Record(ctx, origin.SourceCache)
+		return cached, nil
+	}
+
+	pokemon, err := c.source.GetPokemonByName(ctx, key)
+	if err != nil {
+		return c.fallback(ctx, cached, err)
+	}
+
+	c.store(pokemon, key)
+	return pokemon, nil
+}
+
+// GetSpecies searches for a Pokémon species by ID, using the cache when possible
+func (c *CachedSource) GetSpecies(ctx context.Context, id int) (*types.PokemonSpecies, error) {
+	return cachedResource(ctx, c, c.species, id, c.source.GetSpecies)
+}
+
+// GetEvolutionChain searches for an evolution chain by ID, using the cache when possible
+func (c *CachedSource) GetEvolutionChain(ctx context.Context, id int) (*types.EvolutionChain, error) {
+	return cachedResource(ctx, c, c.chains, id, c.source.GetEvolutionChain)
+}
+
+// GetMove searches for a move by ID, using the cache when possible. A
+// learnset needs dozens of moves, so caching them saves most PokeAPI calls.
+func (c *CachedSource) GetMove(ctx context.Context, id int) (*types.Move, error) {
+	return cachedResource(ctx, c, c.moves, id, c.source.GetMove)
+}
+
+// TransformPokemonToResponse transforms the Pokémon to the response format
+func (c *CachedSource) TransformPokemonToResponse(pokemon *types.Pokemon) *types.PokemonResponse {
+	return c.source.TransformPokemonToResponse(pokemon)
+}
+
+// Stats returns the current cache counters
+func (c *CachedSource) Stats() types.CacheStats {
+	c.mu.Lock()
+	pokemon := c.lru.Len()
+	c.mu.Unlock()
+
+	ttl := "none"
+	if c.ttl > 0 {
+		ttl = c.ttl.String()
+	}
+
+	stats := types.CacheStats{
+		Kinds: map[string]types.CacheKindStats{
+			"pokemon":          {Entries: pokemon, Capacity: c.capacity},
+			"species":          {Entries: c.species.len(), Capacity: c.capacity},
+			"evolution_chains": {Entries: c.chains.len(), Capacity: c.capacity},
+			"moves":            {Entries: c.moves.len(), Capacity: c.capacity},
+		},
+		TTL:    ttl,
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Stale:  c.stale.Load(),
+	}
+	for _, kind := range stats.Kinds {
+		stats.Entries += kind.Entries
+		stats.Capacity += kind.Capacity
+	}
+	return stats
+}
+
+// UpstreamHealth returns the state of the upstream of the wrapped source, if it reports one
+func (c *CachedSource) UpstreamHealth() types.UpstreamHealth {
+	if reporter, ok := c.source.(HealthReporter); ok {
+		return reporter.UpstreamHealth()
+	}
+	return types.UpstreamHealth{}
+}
+
+// Purge removes every entry from the cache and returns how many were removed
+func (c *CachedSource) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := c.lru.Len()
+	c.lru.Init()
+	c.byID = make(map[int]*list.Element)
+	c.byName = make(map[string]*list.Element)
+	return removed + c.species.purge() + c.chains.purge() + c.moves.purge()
+}
+
+// lookup returns the cached Pokémon for the element and whether it is still
+// fresh, updating the counters. Expired entries are returned as not fresh so
+// they can be used as a fallback. The caller must hold the lock.
+func (c *CachedSource) lookup(elem *list.Element) (*types.Pokemon, bool) {
+	if elem == nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expiresAt) {
+		c.misses.Add(1)
+		return entry.pokemon, false
+	}
+
+	c.lru.MoveToFront(elem)
+	c.hits.Add(1)
+	return entry.pokemon, true
+}
+
+// fallback returns the expired entry instead of err when the upstream is unavailable
+func (c *CachedSource) fallback(ctx context.Context, expired *types.Pokemon, err error) (*types.Pokemon, error) {
+	if expired != nil && errors.Is(err, ErrUpstreamUnavailable) {
+		c.stale.Add(1)
+		origin.Record(ctx, origin.SourceCache)
+		return expired, nil
+	}
+	return nil, err
+}
+
+// store adds the Pokémon to the cache under its ID, its name and any extra names
+func (c *CachedSource) store(pokemon *types.Pokemon, names ...string) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.byID[pokemon.ID]; ok {
+		c.remove(elem)
+	}
+
+	entry := &cacheEntry{
+		pokemon:   pokemon,
+		expiresAt: c.now().Add(c.ttl),
+	}
+	for _, name := range append(names, normalizeName(pokemon.Name)) {
+		if name != "" && !contains(entry.names, name) {
+			entry.names = append(entry.names, name)
+		}
+	}
+
+	elem := c.lru.PushFront(entry)
+	c.byID[pokemon.ID] = elem
+	for _, name := range entry.names {
+		c.byName[name] = elem
+	}
+
+	for c.lru.Len() > c.capacity {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove deletes the element from the list and from both indexes
+func (c *CachedSource) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	c.lru.Remove(elem)
+	if c.byID[entry.pokemon.ID] == elem {
+		delete(c.byID, entry.pokemon.ID)
+	}
+	for _, name := range entry.names {
+		if c.byName[name] == elem {
+			delete(c.byName, name)
+		}
+	}
+}
+
+// cachedResource returns the resource with the ID from the cache while it is
+// fresh, or fetches and caches it. Like Pokémon, expired resources are served
+// while the upstream is unavailable.
+func cachedResource[T any](ctx context.Context, c *CachedSource, cache *resourceCache[T], id int, fetch func(context.Context, int) (T, error)) (T, error) {
+	now := c.now()
+
+	cached, found, fresh := cache.get(id, now)
+	if fresh {
+		c.hits.Add(1)
+		origin.Record(ctx, origin.SourceCache)
+		return cached, nil
+	}
+	c.misses.Add(1)
+
+	value, err := fetch(ctx, id)
+	if err != nil {
+		if found && errors.Is(err, ErrUpstreamUnavailable) {
+			c.stale.Add(1)
+			origin.Record(ctx, origin.SourceCache)
+			return cached, nil
+		}
+		return value, err
+	}
+
+	if c.capacity > 0 {
+		var expiresAt time.Time
+		if c.ttl > 0 {
+			expiresAt = now.Add(c.ttl)
+		}
+		cache.put(id, value, expiresAt)
+	}
+	return value, nil
+}
+
+// resourceCache keeps recently used resources of one kind by ID, evicting
+// the least recently used one once full. It is safe for concurrent use.
+type resourceCache[T any] struct {
+	capacity int
+
+	mu   sync.Mutex
+	lru  *list.List
+	byID map[int]*list.Element
+}
+
+// resourceEntry represents a cached resource
+type resourceEntry[T any] struct {
+	id        int
+	value     T
+	expiresAt time.Time
+}
+
+// newResourceCache creates an empty cache holding up to capacity resources
+func newResourceCache[T any](capacity int) *resourceCache[T] {
+	return &resourceCache[T]{
+		capacity: capacity,
+		lru:      list.New(),
+		byID:     make(map[int]*list.Element),
+	}
+}
+
+// get returns the cached resource, whether there is one and whether it is
+// still fresh at the time. Expired resources are returned as not fresh.
+func (rc *resourceCache[T]) get(id int, now time.Time) (value T, found, fresh bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	elem, ok := rc.byID[id]
+	if !ok {
+		return value, false, false
+	}
+
+	entry := elem.Value.(*resourceEntry[T])
+	if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+		return entry.value, true, false
+	}
+
+	rc.lru.MoveToFront(elem)
+	return entry.value, true, true
+}
+
+// put caches the resource until it expires, or forever with a zero expiry
+func (rc *resourceCache[T]) put(id int, value T, expiresAt time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if elem, ok := rc.byID[id]; ok {
+		rc.lru.Remove(elem)
+	}
+	rc.byID[id] = rc.lru.PushFront(&resourceEntry[T]{id: id, value: value, expiresAt: expiresAt})
+
+	for rc.lru.Len() > rc.capacity {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.byID, oldest.Value.(*resourceEntry[T]).id)
+	}
+}
+
+// len returns the number of cached resources
+func (rc *resourceCache[T]) len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return rc.lru.Len()
+}
+
+// purge removes every resource and returns how many were removed
+func (rc *resourceCache[T]) purge() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	removed := rc.lru.Len()
+	rc.lru.Init()
+	rc.byID = make(map[int]*list.Element)
+	return removed
+}
+
+// normalizeName returns the canonical form of a Pokémon name used as a cache key
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// contains reports whether the slice contains the value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/types"
+)
+
+// countingSource counts the calls that reach the underlying source
+type countingSource struct {
+	PokemonSource
+	calls int
+}
+
+func (s *countingSource) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	s.calls++
+	return s.PokemonSource.GetPokemonByID(ctx, id)
+}
+
+func (s *countingSource) GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	s.calls++
+	return s.PokemonSource.GetPokemonByName(ctx, name)
+}
+
+func newCountingSource() *countingSource {
+	return &countingSource{
+		PokemonSource: NewMemorySource(
+			&types.Pokemon{ID: 1, Name: "bulbasaur"},
+			&types.Pokemon{ID: 4, Name: "charmander"},
+			&types.Pokemon{ID: 25, Name: "pikachu"},
+		),
+	}
+}
+
+func TestCachedSource_NameServesID(t *testing.T) {
+	source := newCountingSource()
+	cache := NewCachedSource(source, 10, time.Hour)
+
+	if _, err := cache.GetPokemonByName(context.Background(), " Pikachu "); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	pokemon, err := cache.GetPokemonByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+	}
+
+	if _, err := cache.GetPokemonByName(context.Background(), "pikachu"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if source.calls != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", source.calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Expected 2 hits and 1 miss, got %d hits and %d misses", stats.Hits, stats.Misses)
+	}
+}
+
+func TestCachedSource_TTL(t *testing.T) {
+	source := newCountingSource()
+	cache := NewCachedSource(source, 10, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.GetPokemonByID(context.Background(), 25)
+	cache.GetPokemonByID(context.Background(), 25)
+	if source.calls != 1 {
+		t.Fatalf("Expected 1 upstream call before expiry, got %d", source.calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	cache.GetPokemonByID(context.Background(), 25)
+	if source.calls != 2 {
+		t.Errorf("Expected 2 upstream calls after expiry, got %d", source.calls)
+	}
+}
+
+func TestCachedSource_LRUEviction(t *testing.T) {
+	source := newCountingSource()
+	cache := NewCachedSource(source, 2, time.Hour)
+
+	cache.GetPokemonByID(context.Background(), 1)
+	cache.GetPokemonByID(context.Background(), 4)
+	cache.GetPokemonByID(context.Background(), 1)  // bulbasaur is now the most recently used
+	cache.GetPokemonByID(context.Background(), 25) // evicts charmander
+
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("Expected 2 entries, got %d", stats.Entries)
+	}
+
+	calls := source.calls
+	cache.GetPokemonByName(context.Background(), "bulbasaur")
+	if source.calls != calls {
+		t.Error("Expected bulbasaur to still be cached")
+	}
+
+	cache.GetPokemonByName(context.Background(), "charmander")
+	if source.calls != calls+1 {
+		t.Error("Expected charmander to have been evicted")
+	}
+}
+
+func TestCachedSource_Purge(t *testing.T) {
+	source := newCountingSource()
+	cache := NewCachedSource(source, 10, time.Hour)
+
+	cache.GetPokemonByID(context.Background(), 1)
+	cache.GetPokemonByID(context.Background(), 4)
+
+	if removed := cache.Purge(); removed != 2 {
+		t.Errorf("Expected 2 removed entries, got %d", removed)
+	}
+
+	cache.GetPokemonByID(context.Background(), 1)
+	if source.calls != 3 {
+		t.Errorf("Expected 3 upstream calls after purge, got %d", source.calls)
+	}
+}
+
+func TestCachedSource_ErrorsAreNotCached(t *testing.T) {
+	source := newCountingSource()
+	cache := NewCachedSource(source, 10, time.Hour)
+
+	if _, err := cache.GetPokemonByID(context.Background(), 150); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	cache.GetPokemonByID(context.Background(), 150)
+
+	if source.calls != 2 {
+		t.Errorf("Expected 2 upstream calls, got %d", source.calls)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Expected empty cache, got %d entries", stats.Entries)
+	}
+}
+
+// failingSource fails every lookup with the given error
+type failingSource struct {
+	PokemonSource
+	err error
+}
+
+func (s *failingSource) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.PokemonSource.GetPokemonByID(ctx, id)
+}
+
+func TestCachedSource_ServesStaleWhenUpstreamUnavailable(t *testing.T) {
+	source := &failingSource{PokemonSource: NewMemorySource(&types.Pokemon{ID: 25, Name: "pikachu"})}
+	cache := NewCachedSource(source, 10, time.Minute)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.GetPokemonByID(context.Background(), 25)
+	now = now.Add(2 * time.Minute)
+
+	source.err = &UpstreamError{Path: "/pokemon/25", Kind: ErrUpstreamUnavailable, Cause: ErrCircuitOpen}
+	pokemon, err := cache.GetPokemonByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("Expected the stale entry, got %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+	}
+	if stats := cache.Stats(); stats.Stale != 1 {
+		t.Errorf("Expected 1 stale response, got %d", stats.Stale)
+	}
+
+	// Other errors are not hidden by stale data
+	source.err = &UpstreamError{Path: "/pokemon/25", StatusCode: 404, Kind: ErrNotFound}
+	if _, err := cache.GetPokemonByID(context.Background(), 25); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+// movesSource counts the moves fetched from the underlying source
+type movesSource struct {
+	*MemorySource
+	calls int
+}
+
+func (s *movesSource) GetMove(ctx context.Context, id int) (*types.Move, error) {
+	s.calls++
+	return s.MemorySource.GetMove(ctx, id)
+}
+
+func TestCachedSource_Resources(t *testing.T) {
+	memory, pokemon := newLearnsetSource()
+	source := &movesSource{MemorySource: memory}
+	cache := NewCachedSource(source, 10, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if _, err := Learnset(context.Background(), cache, pokemon, "scarlet-violet", ""); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if source.calls != 5 {
+		t.Errorf("Expected each move to be fetched once, got %d calls", source.calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 5 || stats.Hits != 5 || stats.Misses != 5 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Each kind has its own cache of the configured capacity
+	if stats.Capacity != 40 || stats.Kinds["moves"] != (types.CacheKindStats{Entries: 5, Capacity: 10}) || stats.Kinds["pokemon"].Entries != 0 {
+		t.Errorf("Unexpected capacity %d and kinds %+v", stats.Capacity, stats.Kinds)
+	}
+
+	// Missing moves are not cached
+	cache.GetMove(context.Background(), 9999)
+	cache.GetMove(context.Background(), 9999)
+	if source.calls != 7 {
+		t.Errorf("Expected missing moves to be fetched again, got %d calls", source.calls)
+	}
+
+	if removed := cache.Purge(); removed != 5 {
+		t.Errorf("Expected 5 removed entries, got %d", removed)
+	}
+	cache.GetMove(context.Background(), 84)
+	if source.calls != 8 {
+		t.Errorf("Expected the move to be fetched after purge, got %d calls", source.calls)
+	}
+}
+
+func TestResourceCache_Eviction(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cache := newResourceCache[string](2)
+
+	cache.put(1, "one", now.Add(time.Minute))
+	cache.put(2, "two", time.Time{})
+	cache.get(1, now)
+	cache.put(3, "three", now.Add(time.Minute))
+
+	if _, found, _ := cache.get(2, now); found {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if value, found, fresh := cache.get(1, now.Add(2*time.Minute)); value != "one" || !found || fresh {
+		t.Errorf("Expected an expired entry, got %q (found %v, fresh %v)", value, found, fresh)
+	}
+	if _, _, fresh := cache.get(3, now); !fresh {
+		t.Error("Expected a fresh entry")
+	}
+}
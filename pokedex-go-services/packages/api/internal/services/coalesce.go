@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent calls that share the same key, so only
+// one of them runs while the others wait for its result
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// fetched represents a payload and the source it was read from
+type fetched struct {
+	body   []byte
+	source string
+}
+
+// flightCall represents a call that is in flight
+type flightCall struct {
+	done    chan struct{}
+	result  fetched
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do runs fn once per key at a time. Callers that arrive while a call for the
+// same key is in flight wait for it and receive the same result or error.
+//
+// The shared call is detached from the cancellation of any single caller: a
+// caller whose context is done stops waiting and gets the context error, and
+// the call itself is cancelled only once every caller has stopped waiting.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (fetched, error)) (fetched, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = call
+
+		go func() {
+			result, err := fn(callCtx)
+
+			g.mu.Lock()
+			call.result, call.err = result, err
+			g.forget(key, call)
+			g.mu.Unlock()
+
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			call.cancel()
+			g.forget(key, call)
+		}
+		g.mu.Unlock()
+		return fetched{}, ctx.Err()
+	}
+}
+
+// forget removes the call from the group so later callers start a new one.
+// The caller must hold the lock.
+func (g *flightGroup) forget(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+// waiters returns how many callers are sharing the call in flight for the key
+func (g *flightGroup) waiters(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if call, ok := g.calls[key]; ok {
+		return call.waiters
+	}
+	return 0
+}
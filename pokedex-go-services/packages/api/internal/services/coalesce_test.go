@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/config"
+)
+
+// newBlockingServer returns a server that counts hits and holds every response
+// until release is closed
+func newBlockingServer(status int, body string, release <-chan struct{}) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	return server, &hits
+}
+
+// waitForWaiters blocks until n callers share the flight for the key
+func waitForWaiters(t *testing.T, service *PokeAPIService, key string, n int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for service.flights.waiters(key) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d waiters for %s, got %d", n, key, service.flights.waiters(key))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetPokemonByID_CoalescesConcurrentRequests(t *testing.T) {
+	release := make(chan struct{})
+	server, hits := newBlockingServer(http.StatusOK, `{"id": 25, "name": "pikachu"}`, release)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	const callers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pokemon, err := service.GetPokemonByID(context.Background(), 25)
+			if err == nil && pokemon.Name != "pikachu" {
+				t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+			}
+			errs <- err
+		}()
+	}
+
+	waitForWaiters(t, service, "/pokemon/25", callers)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	}
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
+
+func TestGetPokemonByID_CoalescedCallersShareErrors(t *testing.T) {
+	release := make(chan struct{})
+	server, hits := newBlockingServer(http.StatusNotFound, `{"detail": "Not found."}`, release)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	var failures atomic.Int32
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.GetPokemonByID(context.Background(), 99999); err != nil {
+				failures.Add(1)
+			}
+		}()
+	}
+
+	waitForWaiters(t, service, "/pokemon/99999", callers)
+	close(release)
+	wg.Wait()
+
+	if got := failures.Load(); got != callers {
+		t.Errorf("Expected %d failed callers, got %d", callers, got)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
+
+func TestGetPokemonByID_SequentialRequestsAreNotCoalesced(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	server, hits := newBlockingServer(http.StatusOK, `{"id": 25, "name": "pikachu"}`, release)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	service.GetPokemonByID(context.Background(), 25)
+	service.GetPokemonByID(context.Background(), 25)
+
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected 2 upstream requests, got %d", got)
+	}
+}
+
+func TestGetPokemonByName_CoalescesMixedCase(t *testing.T) {
+	release := make(chan struct{})
+	server, hits := newBlockingServer(http.StatusOK, `{"id": 25, "name": "pikachu"}`, release)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"pikachu", "Pikachu", "PIKACHU"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := service.GetPokemonByName(context.Background(), name); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}(name)
+	}
+
+	waitForWaiters(t, service, "/pokemon/pikachu", 3)
+	close(release)
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
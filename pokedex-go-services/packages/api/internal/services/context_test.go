@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/config"
+)
+
+// newHangingServer returns a server that never answers and reports on the
+// returned channel when a request is cancelled by the client
+func newHangingServer() (*httptest.Server, <-chan struct{}) {
+	cancelled := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled <- struct{}{}
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	return server, cancelled
+}
+
+func TestGetPokemonByID_CancelStopsUpstreamRequest(t *testing.T) {
+	server, cancelled := newHangingServer()
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := service.GetPokemonByID(ctx, 25)
+		errs <- err
+	}()
+
+	waitForWaiters(t, service, "/pokemon/25", 1)
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the upstream request to be cancelled")
+	}
+}
+
+func TestGetPokemonByID_CancelledWaiterDoesNotAffectOthers(t *testing.T) {
+	release := make(chan struct{})
+	server, hits := newBlockingServer(http.StatusOK, `{"id": 25, "name": "pikachu"}`, release)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	var cancelledErr, sharedErr error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, cancelledErr = service.GetPokemonByID(ctx, 25)
+	}()
+	go func() {
+		defer wg.Done()
+		_, sharedErr = service.GetPokemonByID(context.Background(), 25)
+	}()
+
+	waitForWaiters(t, service, "/pokemon/25", 2)
+	cancel()
+	waitForWaiters(t, service, "/pokemon/25", 1)
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(cancelledErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled for the cancelled caller, got %v", cancelledErr)
+	}
+	if sharedErr != nil {
+		t.Errorf("Expected no error for the remaining caller, got %v", sharedErr)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+}
+
+func TestGetPokemonByID_UpstreamTimeout(t *testing.T) {
+	server, _ := newHangingServer()
+	defer server.Close()
+
+	cfg := &config.Config{PokeAPIBaseURL: server.URL, UpstreamTimeout: 50 * time.Millisecond}
+	service := NewPokeAPIService(cfg)
+
+	start := time.Now()
+	_, err := service.GetPokemonByID(context.Background(), 25)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the request to time out quickly, took %v", elapsed)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors returned by the Pokémon sources. Use errors.Is to check them.
+var (
+	// ErrNotFound means the requested resource does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrUpstreamUnavailable means the PokeAPI could not be reached or failed
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrRateLimited means the PokeAPI rejected the request due to rate limiting
+	ErrRateLimited = errors.New("upstream rate limit exceeded")
+	// ErrDecode means the PokeAPI returned a payload that could not be decoded
+	ErrDecode = errors.New("invalid upstream payload")
+	// ErrInvalidID means a Pokémon ID is not a number or is out of range
+	ErrInvalidID = errors.New("invalid ID")
+	// ErrMoveNotFound means a move the Pokémon learns does not exist in the
+	// PokeAPI. It also matches ErrNotFound.
+	ErrMoveNotFound = errors.New("move not found")
+)
+
+// UpstreamError represents a failed call to the PokeAPI. It matches one of
+// the sentinel errors with errors.Is and keeps the underlying cause.
+type UpstreamError struct {
+	Path       string
+	StatusCode int           // Zero when no response was received
+	RetryAfter time.Duration // Wait requested by the Retry-After header, if any
+	Kind       error
+	Cause      error
+}
+
+// Error returns the error message
+func (e *UpstreamError) Error() string {
+	msg := fmt.Sprintf("%s: %v", e.Path, e.Kind)
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error and the underlying cause
+func (e *UpstreamError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Cause}
+}
+
+// errorForResponse returns the error for a non-200 response from the PokeAPI
+func errorForResponse(path string, resp *http.Response) *UpstreamError {
+	kind := ErrUpstreamUnavailable
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		kind = ErrNotFound
+	case http.StatusTooManyRequests:
+		kind = ErrRateLimited
+	}
+
+	return &UpstreamError{
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		Kind:       kind,
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+)
+
+func TestGetPokemonByID_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"not found", http.StatusNotFound, `{"detail": "Not found."}`, ErrNotFound},
+		{"rate limited", http.StatusTooManyRequests, ``, ErrRateLimited},
+		{"server error", http.StatusInternalServerError, ``, ErrUpstreamUnavailable},
+		{"bad gateway", http.StatusBadGateway, ``, ErrUpstreamUnavailable},
+		{"invalid JSON", http.StatusOK, `{"id": "twenty-five"`, ErrDecode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+			_, err := service.GetPokemonByID(context.Background(), 25)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+
+			var upstreamErr *UpstreamError
+			if !errors.As(err, &upstreamErr) {
+				t.Fatalf("Expected *UpstreamError, got %T", err)
+			}
+			if upstreamErr.Path != "/pokemon/25" {
+				t.Errorf("Expected path '/pokemon/25', got %s", upstreamErr.Path)
+			}
+		})
+	}
+}
+
+func TestGetPokemonByID_ConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	_, err := service.GetPokemonByID(context.Background(), 25)
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestUpstreamError_Message(t *testing.T) {
+	err := &UpstreamError{Path: "/pokemon/25", StatusCode: 404, Kind: ErrNotFound}
+	if got := err.Error(); got != "/pokemon/25: not found (status 404)" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"pokedexia-backend/internal/types"
+)
+
+// FirstFormID is the first ID the PokeAPI gives to alternate forms, such as
+// megas and regional forms
+const FirstFormID = 10001
+
+// speciesCountPath returns the number of species without listing them
+const speciesCountPath = "/pokemon-species?limit=1"
+
+// pokemonListPath lists every Pokémon, forms included, in a single page
+const pokemonListPath = "/pokemon?limit=100000"
+
+// PokemonIDs represents the set of valid Pokémon IDs: the national Pokédex
+// numbers from 1 to the number of species, and the IDs of alternate forms.
+// Until the forms are known, every ID from FirstFormID is accepted and left
+// for the PokeAPI to resolve. It is safe for concurrent use.
+type PokemonIDs struct {
+	mu    sync.RWMutex
+	max   int
+	forms map[int]bool
+}
+
+// NewPokemonIDs creates a set of IDs from 1 to max, with the forms unknown
+func NewPokemonIDs(max int) *PokemonIDs {
+	return &PokemonIDs{max: max}
+}
+
+// Set replaces the highest national Pokédex number and the form IDs
+func (ids *PokemonIDs) Set(max int, forms []int) {
+	known := make(map[int]bool, len(forms))
+	for _, id := range forms {
+		known[id] = true
+	}
+
+	ids.mu.Lock()
+	defer ids.mu.Unlock()
+
+	ids.max = max
+	ids.forms = known
+}
+
+// Max returns the highest national Pokédex number
+func (ids *PokemonIDs) Max() int {
+	ids.mu.RLock()
+	defer ids.mu.RUnlock()
+
+	return ids.max
+}
+
+// All returns every valid ID in order: the national Pokédex numbers and the
+// IDs of the forms, once they are known
+func (ids *PokemonIDs) All() []int {
+	ids.mu.RLock()
+	defer ids.mu.RUnlock()
+
+	all := make([]int, 0, ids.max+len(ids.forms))
+	for id := 1; id <= ids.max; id++ {
+		all = append(all, id)
+	}
+
+	forms := make([]int, 0, len(ids.forms))
+	for id := range ids.forms {
+		forms = append(forms, id)
+	}
+	sort.Ints(forms)
+	return append(all, forms...)
+}
+
+// Contains reports whether the ID is a national Pokédex number or a form ID
+func (ids *PokemonIDs) Contains(id int) bool {
+	ids.mu.RLock()
+	defer ids.mu.RUnlock()
+
+	if id >= 1 && id <= ids.max {
+		return true
+	}
+	if ids.forms == nil {
+		return id >= FirstFormID
+	}
+	return ids.forms[id]
+}
+
+// Validate parses the ID and checks that it is in the set
+func (ids *PokemonIDs) Validate(idStr string) (int, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidID, idStr)
+	}
+
+	if !ids.Contains(id) {
+		return 0, fmt.Errorf("%w: must be between 1 and %d or a form ID", ErrInvalidID, ids.Max())
+	}
+
+	return id, nil
+}
+
+// IDs returns the set of valid Pokémon IDs, which RefreshIDs keeps up to date
+func (s *PokeAPIService) IDs() *PokemonIDs {
+	return s.ids
+}
+
+// RefreshIDs discovers the valid Pokémon IDs from the species count and the
+// list of every Pokémon
+func (s *PokeAPIService) RefreshIDs(ctx context.Context) error {
+	count, err := s.fetchList(ctx, speciesCountPath)
+	if err != nil {
+		return err
+	}
+
+	pokemon, err := s.fetchList(ctx, pokemonListPath)
+	if err != nil {
+		return err
+	}
+
+	var forms []int
+	for _, p := range pokemon.Results {
+		if id := ResourceID(p.URL); id > count.Count {
+			forms = append(forms, id)
+		}
+	}
+
+	s.ids.Set(count.Count, forms)
+	return nil
+}
+
+// fetchList fetches a page of a resource listing
+func (s *PokeAPIService) fetchList(ctx context.Context, path string) (*types.NamedResourceList, error) {
+	body, err := s.fetchResource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list types.NamedResourceList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, &UpstreamError{Path: path, Kind: ErrDecode, Cause: err}
+	}
+
+	return &list, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+)
+
+func TestPokemonIDs(t *testing.T) {
+	ids := NewPokemonIDs(151)
+
+	// Any form ID is accepted until the forms are known
+	for id, expected := range map[int]bool{0: false, 1: true, 151: true, 152: false, 10001: true, 10500: true} {
+		if got := ids.Contains(id); got != expected {
+			t.Errorf("Contains(%d) = %v, expected %v", id, got, expected)
+		}
+	}
+
+	ids.Set(1025, []int{10001, 10002})
+
+	for id, expected := range map[int]bool{152: true, 1025: true, 1026: false, 10001: true, 10002: true, 10003: false} {
+		if got := ids.Contains(id); got != expected {
+			t.Errorf("Contains(%d) = %v, expected %v", id, got, expected)
+		}
+	}
+	if ids.Max() != 1025 {
+		t.Errorf("Expected max 1025, got %d", ids.Max())
+	}
+
+	if _, err := ids.Validate("10003"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+	if id, err := ids.Validate("10002"); err != nil || id != 10002 {
+		t.Errorf("Expected 10002, got %d, %v", id, err)
+	}
+
+	ids.Set(3, []int{10002, 10001})
+	if all := ids.All(); fmt.Sprint(all) != "[1 2 3 10001 10002]" {
+		t.Errorf("Expected the national numbers and the forms in order, got %v", all)
+	}
+}
+
+func TestRefreshIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon-species":
+			fmt.Fprint(w, `{"count": 1030, "results": [{"name": "bulbasaur", "url": "https://pokeapi.co/api/v2/pokemon-species/1/"}]}`)
+		case "/pokemon":
+			fmt.Fprint(w, `{"count": 3, "results": [
+				{"name": "bulbasaur", "url": "https://pokeapi.co/api/v2/pokemon/1/"},
+				{"name": "venusaur-mega", "url": "https://pokeapi.co/api/v2/pokemon/10033/"},
+				{"name": "raichu-alola", "url": "https://pokeapi.co/api/v2/pokemon/10100/"}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	if err := service.RefreshIDs(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ids := service.IDs()
+	if ids.Max() != 1030 {
+		t.Errorf("Expected max 1030, got %d", ids.Max())
+	}
+	if _, err := service.ValidatePokemonID("1030"); err != nil {
+		t.Errorf("Expected a new species to be valid, got %v", err)
+	}
+	if _, err := service.ValidatePokemonID("10100"); err != nil {
+		t.Errorf("Expected a form to be valid, got %v", err)
+	}
+	if _, err := service.ValidatePokemonID("10001"); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected an unknown form to be invalid, got %v", err)
+	}
+}
+
+func TestRefreshIDs_KeepsBoundsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	if err := service.RefreshIDs(context.Background()); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if service.IDs().Max() != MaxPokemonID {
+		t.Errorf("Expected max %d, got %d", MaxPokemonID, service.IDs().Max())
+	}
+}
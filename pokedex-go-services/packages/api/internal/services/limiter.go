@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"pokedexia-backend/internal/types"
+)
+
+// ErrThrottled is the cause of the errors returned when a request waited too
+// long for the outbound rate limiter. It always comes wrapped with ErrRateLimited.
+var ErrThrottled = errors.New("outbound rate limit wait exceeded")
+
+// RateLimiter is a token bucket that throttles requests to the PokeAPI, as
+// asked by its fair-use policy. Requests wait in line for a token; if the
+// wait would exceed the maximum they are rejected instead.
+type RateLimiter struct {
+	limiter *rate.Limiter
+	maxWait time.Duration
+
+	mu        sync.Mutex
+	requests  uint64
+	delayed   uint64
+	rejected  uint64
+	totalWait time.Duration
+	maxWaited time.Duration
+}
+
+// NewRateLimiter creates a limiter allowing the given requests per second with the given burst
+func NewRateLimiter(perSecond float64, burst int, maxWait time.Duration) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(perSecond), burst),
+		maxWait: maxWait,
+	}
+}
+
+// wait blocks until the request may be sent, returning ErrThrottled if that
+// would take longer than the maximum wait, or the context error if it is done first
+func (l *RateLimiter) wait(ctx context.Context) error {
+	reservation := l.limiter.Reserve()
+	delay := reservation.Delay()
+
+	if l.maxWait > 0 && delay > l.maxWait {
+		reservation.Cancel()
+		l.observe(0, true)
+		return ErrThrottled
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			reservation.Cancel()
+			return ctx.Err()
+		}
+	}
+
+	l.observe(delay, false)
+	return nil
+}
+
+// observe records the outcome of a wait
+func (l *RateLimiter) observe(waited time.Duration, rejected bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests++
+	if rejected {
+		l.rejected++
+		return
+	}
+	if waited > 0 {
+		l.delayed++
+		l.totalWait += waited
+		if waited > l.maxWaited {
+			l.maxWaited = waited
+		}
+	}
+}
+
+// Status returns the limiter settings and wait metrics for the health check
+func (l *RateLimiter) Status() types.RateLimiterStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := types.RateLimiterStatus{
+		Limit:     float64(l.limiter.Limit()),
+		Burst:     l.limiter.Burst(),
+		Requests:  l.requests,
+		Delayed:   l.delayed,
+		Rejected:  l.rejected,
+		MaxWaitMS: float64(l.maxWaited) / float64(time.Millisecond),
+	}
+	if admitted := l.requests - l.rejected; admitted > 0 {
+		status.AverageWaitMS = float64(l.totalWait) / float64(admitted) / float64(time.Millisecond)
+	}
+	return status
+}
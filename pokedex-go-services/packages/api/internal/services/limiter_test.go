@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Waits(t *testing.T) {
+	limiter := NewRateLimiter(20, 1, time.Second)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// The burst lets the first request through, the next two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected requests to be throttled, took %v", elapsed)
+	}
+
+	status := limiter.Status()
+	if status.Requests != 3 || status.Delayed != 2 || status.Rejected != 0 {
+		t.Errorf("Unexpected counters %+v", status)
+	}
+	if status.MaxWaitMS <= 0 || status.AverageWaitMS <= 0 {
+		t.Errorf("Expected wait metrics to be recorded, got %+v", status)
+	}
+}
+
+func TestRateLimiter_RejectsLongWaits(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, 10*time.Millisecond)
+
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+	if err := limiter.wait(context.Background()); !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected ErrThrottled, got %v", err)
+	}
+	if status := limiter.Status(); status.Rejected != 1 {
+		t.Errorf("Expected 1 rejected request, got %d", status.Rejected)
+	}
+}
+
+func TestRateLimiter_Cancelled(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, time.Minute)
+	limiter.wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPokeAPIService_Throttled(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	cfg := newRetryConfig(server.URL)
+	cfg.BreakerFailureThreshold = 1
+	cfg.UpstreamRateLimit = 1
+	cfg.UpstreamRateBurst = 1
+	cfg.UpstreamRateMaxWait = 10 * time.Millisecond
+	service := NewPokeAPIService(cfg)
+
+	if _, err := service.GetPokemonByID(context.Background(), 25); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err := service.GetPokemonByID(context.Background(), 1)
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrThrottled) {
+		t.Errorf("Expected ErrThrottled wrapped as ErrRateLimited, got %v", err)
+	}
+
+	// Throttled requests are neither retried nor counted against the PokeAPI
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", got)
+	}
+	if state := service.breaker.State(); state != BreakerClosed {
+		t.Errorf("Expected closed breaker, got %s", state)
+	}
+
+	health := service.UpstreamHealth()
+	if health.RateLimiter == nil || health.RateLimiter.Rejected != 1 {
+		t.Errorf("Expected the rejection in the health report, got %+v", health.RateLimiter)
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"pokedexia-backend/internal/origin"
+	"pokedexia-backend/internal/types"
+)
+
+// MemorySource is an in-memory PokemonSource, useful for tests and local development
+type MemorySource struct {
+	mu      sync.RWMutex
+	byID    map[int]*types.Pokemon
+	byName  map[string]*types.Pokemon
+	species map[int]*types.PokemonSpecies
+	chains  map[int]*types.EvolutionChain
+	moves   map[int]*types.Move
+}
+
+// Ensure the memory source satisfies the interface
+var _ PokemonSource = (*MemorySource)(nil)
+
+// NewMemorySource creates a new in-memory source with the given Pokémon
+func NewMemorySource(pokemons ...*types.Pokemon) *MemorySource {
+	s := &MemorySource{
+		byID:    make(map[int]*types.Pokemon),
+		byName:  make(map[string]*types.Pokemon),
+		species: make(map[int]*types.PokemonSpecies),
+		chains:  make(map[int]*types.EvolutionChain),
+		moves:   make(map[int]*types.Move),
+	}
+	for _, p := range pokemons {
+		s.Add(p)
+	}
+	return s
+}
+
+// Add stores a Pokémon, replacing any previous entry with the same ID or name
+func (s *MemorySource) Add(pokemon *types.Pokemon) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.byID[pokemon.ID] = pokemon
+	s.byName[strings.ToLower(pokemon.Name)] = pokemon
+}
+
+// AddSpecies stores a species, replacing any previous entry with the same ID
+func (s *MemorySource) AddSpecies(species *types.PokemonSpecies) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.species[species.ID] = species
+}
+
+// AddEvolutionChain stores an evolution chain, replacing any previous entry with the same ID
+func (s *MemorySource) AddEvolutionChain(chain *types.EvolutionChain) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chains[chain.ID] = chain
+}
+
+// AddMove stores a move, replacing any previous entry with the same ID
+func (s *MemorySource) AddMove(move *types.Move) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.moves[move.ID] = move
+}
+
+// GetPokemonByID searches for a Pokémon by ID
+func (s *MemorySource) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pokemon, ok := s.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("pokemon %d: %w", id, ErrNotFound)
+	}
+	origin.Record(ctx, origin.SourceMemory)
+	return pokemon, nil
+}
+
+// GetPokemonByName searches for a Pokémon by name
+func (s *MemorySource) GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pokemon, ok := s.byName[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("pokemon %q: %w", name, ErrNotFound)
+	}
+	origin.Record(ctx, origin.SourceMemory)
+	return pokemon, nil
+}
+
+// GetSpecies searches for a Pokémon species by ID
+func (s *MemorySource) GetSpecies(ctx context.Context, id int) (*types.PokemonSpecies, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	species, ok := s.species[id]
+	if !ok {
+		return nil, fmt.Errorf("species %d: %w", id, ErrNotFound)
+	}
+	origin.Record(ctx, origin.SourceMemory)
+	return species, nil
+}
+
+// GetEvolutionChain searches for an evolution chain by ID
+func (s *MemorySource) GetEvolutionChain(ctx context.Context, id int) (*types.EvolutionChain, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	chain, ok := s.chains[id]
+	if !ok {
+		return nil, fmt.Errorf("evolution chain %d: %w", id, ErrNotFound)
+	}
+	origin.Record(ctx, origin.SourceMemory)
+	return chain, nil
+}
+
+// GetMove searches for a move by ID
+func (s *MemorySource) GetMove(ctx context.Context, id int) (*types.Move, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	move, ok := s.moves[id]
+	if !ok {
+		return nil, fmt.Errorf("move %d: %w", id, ErrNotFound)
+	}
+	origin.Record(ctx, origin.SourceMemory)
+	return move, nil
+}
+
+// TransformPokemonToResponse transforms the Pokémon to the response format
+func (s *MemorySource) TransformPokemonToResponse(pokemon *types.Pokemon) *types.PokemonResponse {
+	return TransformPokemonToResponse(pokemon)
+}
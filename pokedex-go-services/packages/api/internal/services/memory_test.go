@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"pokedexia-backend/internal/types"
+)
+
+func TestMemorySource(t *testing.T) {
+	source := NewMemorySource(&types.Pokemon{ID: 25, Name: "pikachu"})
+
+	pokemon, err := source.GetPokemonByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+	}
+
+	pokemon, err = source.GetPokemonByName(context.Background(), "PIKACHU")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.ID != 25 {
+		t.Errorf("Expected ID 25, got %d", pokemon.ID)
+	}
+
+	if _, err := source.GetPokemonByID(context.Background(), 26); err == nil {
+		t.Error("Expected error for unknown ID, got nil")
+	}
+
+	if _, err := source.GetPokemonByName(context.Background(), "raichu"); err == nil {
+		t.Error("Expected error for unknown name, got nil")
+	}
+}
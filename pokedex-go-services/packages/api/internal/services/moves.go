@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"pokedexia-backend/internal/types"
+)
+
+// MethodLevelUp is the learn method of moves learned by leveling up
+const MethodLevelUp = "level-up"
+
+// learnsetConcurrency limits how many moves of a learnset are fetched at once
+const learnsetConcurrency = 8
+
+// GetMove searches for a move by ID
+func (s *PokeAPIService) GetMove(ctx context.Context, id int) (*types.Move, error) {
+	path := fmt.Sprintf("/move/%d", id)
+
+	body, err := s.fetchResource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var move types.Move
+	if err := json.Unmarshal(body, &move); err != nil {
+		return nil, &UpstreamError{Path: path, Kind: ErrDecode, Cause: err}
+	}
+
+	return &move, nil
+}
+
+// Learnset returns the moves the Pokémon learns in a version group, resolving
+// the details of each move from the source. An empty method includes every
+// learn method. Moves are sorted by level, with level-up moves first.
+func Learnset(ctx context.Context, source PokemonSource, pokemon *types.Pokemon, versionGroup, method string) (*types.LearnsetResponse, error) {
+	var learned []types.LearnedMove
+	for _, move := range pokemon.Moves {
+		for _, detail := range move.VersionGroupDetails {
+			if detail.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && detail.MoveLearnMethod.Name != method {
+				continue
+			}
+			learned = append(learned, types.LearnedMove{
+				ID:     ResourceID(move.Move.URL),
+				Name:   move.Move.Name,
+				Method: detail.MoveLearnMethod.Name,
+				Level:  detail.LevelLearnedAt,
+			})
+		}
+	}
+
+	if err := resolveMoves(ctx, source, learned); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(learned, func(i, j int) bool {
+		a, b := learned[i], learned[j]
+		if (a.Method == MethodLevelUp) != (b.Method == MethodLevelUp) {
+			return a.Method == MethodLevelUp
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.Name < b.Name
+	})
+
+	return &types.LearnsetResponse{
+		PokemonID:    pokemon.ID,
+		Pokemon:      pokemon.Name,
+		VersionGroup: versionGroup,
+		Method:       method,
+		Moves:        learned,
+	}, nil
+}
+
+// resolveMoves fills in the details of each move, fetching a few at a time.
+// The first error cancels the remaining requests.
+func resolveMoves(ctx context.Context, source PokemonSource, learned []types.LearnedMove) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		slots    = make(chan struct{}, learnsetConcurrency)
+	)
+
+	for i := range learned {
+		wg.Add(1)
+		go func(lm *types.LearnedMove) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return
+			}
+
+			move, err := source.GetMove(ctx, lm.ID)
+			if err != nil {
+				if errors.Is(err, ErrNotFound) {
+					err = fmt.Errorf("%w: %d: %w", ErrMoveNotFound, lm.ID, err)
+				}
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+
+			lm.Type = move.Type.Name
+			lm.DamageClass = move.DamageClass.Name
+			lm.Power = move.Power
+			lm.Accuracy = move.Accuracy
+			lm.PP = move.PP
+		}(&learned[i])
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+	"pokedexia-backend/internal/types"
+)
+
+func learnableMove(id int, name string, details ...types.MoveVersionDetail) types.PokemonMove {
+	return types.PokemonMove{
+		Move:                types.NamedResource{Name: name, URL: fmt.Sprintf("https://pokeapi.co/api/v2/move/%d/", id)},
+		VersionGroupDetails: details,
+	}
+}
+
+func learnedIn(versionGroup, method string, level int) types.MoveVersionDetail {
+	return types.MoveVersionDetail{
+		LevelLearnedAt:  level,
+		MoveLearnMethod: types.NamedResource{Name: method},
+		VersionGroup:    types.NamedResource{Name: versionGroup},
+	}
+}
+
+func newLearnsetSource() (*MemorySource, *types.Pokemon) {
+	pokemon := &types.Pokemon{
+		ID:   25,
+		Name: "pikachu",
+		Moves: []types.PokemonMove{
+			learnableMove(85, "thunderbolt", learnedIn("scarlet-violet", "machine", 0)),
+			learnableMove(98, "quick-attack", learnedIn("scarlet-violet", MethodLevelUp, 1), learnedIn("red-blue", MethodLevelUp, 16)),
+			learnableMove(87, "thunder", learnedIn("scarlet-violet", MethodLevelUp, 32)),
+			learnableMove(84, "thunder-shock", learnedIn("scarlet-violet", MethodLevelUp, 1)),
+			learnableMove(86, "thunder-wave", learnedIn("scarlet-violet", MethodLevelUp, 4)),
+		},
+	}
+
+	source := NewMemorySource(pokemon)
+	for _, move := range []*types.Move{
+		{ID: 84, Name: "thunder-shock", Power: intPtr(40), Accuracy: intPtr(100), PP: intPtr(30), Type: types.NamedResource{Name: "electric"}, DamageClass: types.NamedResource{Name: "special"}},
+		{ID: 85, Name: "thunderbolt", Power: intPtr(90), Accuracy: intPtr(100), PP: intPtr(15), Type: types.NamedResource{Name: "electric"}, DamageClass: types.NamedResource{Name: "special"}},
+		{ID: 86, Name: "thunder-wave", Accuracy: intPtr(90), PP: intPtr(20), Type: types.NamedResource{Name: "electric"}, DamageClass: types.NamedResource{Name: "status"}},
+		{ID: 87, Name: "thunder", Power: intPtr(110), Accuracy: intPtr(70), PP: intPtr(10), Type: types.NamedResource{Name: "electric"}, DamageClass: types.NamedResource{Name: "special"}},
+		{ID: 98, Name: "quick-attack", Power: intPtr(40), Accuracy: intPtr(100), PP: intPtr(30), Type: types.NamedResource{Name: "normal"}, DamageClass: types.NamedResource{Name: "physical"}},
+	} {
+		source.AddMove(move)
+	}
+	return source, pokemon
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestLearnset_LevelUp(t *testing.T) {
+	source, pokemon := newLearnsetSource()
+
+	learnset, err := Learnset(context.Background(), source, pokemon, "scarlet-violet", MethodLevelUp)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"quick-attack", "thunder-shock", "thunder-wave", "thunder"}
+	if len(learnset.Moves) != len(expected) {
+		t.Fatalf("Expected %d moves, got %d", len(expected), len(learnset.Moves))
+	}
+	for i, name := range expected {
+		if learnset.Moves[i].Name != name {
+			t.Errorf("Expected move %d to be %s, got %s", i, name, learnset.Moves[i].Name)
+		}
+	}
+
+	thunder := learnset.Moves[3]
+	if thunder.Level != 32 || thunder.Type != "electric" || thunder.DamageClass != "special" {
+		t.Errorf("Unexpected move %+v", thunder)
+	}
+	if *thunder.Power != 110 || *thunder.Accuracy != 70 || *thunder.PP != 10 {
+		t.Errorf("Unexpected power/accuracy/PP for %+v", thunder)
+	}
+	if learnset.Moves[2].Power != nil {
+		t.Errorf("Expected status move without power, got %d", *learnset.Moves[2].Power)
+	}
+}
+
+func TestLearnset_AllMethods(t *testing.T) {
+	source, pokemon := newLearnsetSource()
+
+	learnset, err := Learnset(context.Background(), source, pokemon, "scarlet-violet", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(learnset.Moves) != 5 {
+		t.Fatalf("Expected 5 moves, got %d", len(learnset.Moves))
+	}
+	if last := learnset.Moves[4]; last.Name != "thunderbolt" || last.Method != "machine" {
+		t.Errorf("Expected machine moves after level-up moves, got %+v", last)
+	}
+
+	learnset, err = Learnset(context.Background(), source, pokemon, "red-blue", "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(learnset.Moves) != 1 || learnset.Moves[0].Level != 16 {
+		t.Errorf("Expected quick-attack at level 16, got %+v", learnset.Moves)
+	}
+}
+
+func TestLearnset_MissingMove(t *testing.T) {
+	source, pokemon := newLearnsetSource()
+	pokemon.Moves = append(pokemon.Moves, learnableMove(9999, "unknown", learnedIn("scarlet-violet", MethodLevelUp, 50)))
+
+	_, err := Learnset(context.Background(), source, pokemon, "scarlet-violet", MethodLevelUp)
+	if !errors.Is(err, ErrMoveNotFound) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrMoveNotFound, got %v", err)
+	}
+}
+
+func TestGetMove(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/move/85" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id": 85, "name": "thunderbolt", "accuracy": 100, "power": 90, "pp": 15, "priority": 0,
+			"type": {"name": "electric", "url": ""}, "damage_class": {"name": "special", "url": ""}}`))
+	}))
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	move, err := service.GetMove(context.Background(), 85)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if move.Name != "thunderbolt" || *move.Power != 90 || *move.PP != 15 || move.DamageClass.Name != "special" {
+		t.Errorf("Unexpected move %+v", move)
+	}
+
+	if _, err := service.GetMove(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+	"pokedexia-backend/internal/store"
+)
+
+// mapStore is an in-memory store.Store used by the tests
+type mapStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{values: make(map[string][]byte)}
+}
+
+func (s *mapStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s *mapStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+func (s *mapStore) Close() error {
+	return nil
+}
+
+func TestPokeAPIService_PersistsPayloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	}))
+	defer server.Close()
+
+	st := newMapStore()
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(st)
+
+	if _, err := service.GetPokemonByName(context.Background(), "Pikachu"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := st.Get("pokemon/25"); err != nil {
+		t.Errorf("Expected payload stored under its ID, got %v", err)
+	}
+	if _, err := st.Get("pokemon/pikachu"); err == nil {
+		t.Error("Expected names to be stored as aliases, not as payload copies")
+	}
+	if target, err := st.Get("alias/pokemon/pikachu"); err != nil || string(target) != "pokemon/25" {
+		t.Errorf("Expected alias to point to pokemon/25, got %s (%v)", target, err)
+	}
+}
+
+func TestPokeAPIService_Offline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no upstream request in offline mode, got %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	st := newMapStore()
+	st.Put("pokemon/25", []byte(`{"id": 25, "name": "pikachu"}`))
+	st.Put("alias/pokemon/pikachu", []byte("pokemon/25"))
+
+	cfg := &config.Config{PokeAPIBaseURL: server.URL, Offline: true}
+	service := NewPokeAPIService(cfg).WithStore(st)
+
+	pokemon, err := service.GetPokemonByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+	}
+
+	pokemon, err = service.GetPokemonByName(context.Background(), "PIKACHU")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.ID != 25 {
+		t.Errorf("Expected ID 25, got %d", pokemon.ID)
+	}
+
+	if _, err := service.GetPokemonByID(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a Pokémon missing from the store, got %v", err)
+	}
+}
+
+func TestPokeAPIService_OfflineWithoutStore(t *testing.T) {
+	service := NewPokeAPIService(&config.Config{Offline: true})
+
+	if _, err := service.GetPokemonByID(context.Background(), 25); !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable without a local store, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/origin"
+)
+
+func TestOrigin_RecordsSources(t *testing.T) {
+	cache := NewCachedSource(newCountingSource(), 10, time.Hour)
+
+	ctx, tracked := origin.With(context.Background())
+	if _, err := cache.GetPokemonByID(ctx, 25); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tracked.Source() != origin.SourceMemory {
+		t.Errorf("expected source %q, got %q", origin.SourceMemory, tracked.Source())
+	}
+	if tracked.Cached() {
+		t.Error("expected a miss not to be cached")
+	}
+
+	ctx, tracked = origin.With(context.Background())
+	if _, err := cache.GetPokemonByID(ctx, 25); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tracked.Source() != origin.SourceCache {
+		t.Errorf("expected source %q, got %q", origin.SourceCache, tracked.Source())
+	}
+	if !tracked.Cached() {
+		t.Error("expected a hit to be cached")
+	}
+}
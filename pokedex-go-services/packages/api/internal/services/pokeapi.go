@@ -1,90 +1,288 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"pokedexia-backend/internal/config"
+	"pokedexia-backend/internal/origin"
+	"pokedexia-backend/internal/store"
 	"pokedexia-backend/internal/types"
 )
 
+// MaxPokemonID is the highest national Pokédex number known when the API was
+// built. The service discovers the current one with RefreshIDs.
+const MaxPokemonID = 1025
+
+// defaultUpstreamTimeout is used when the configuration does not set a timeout
+const defaultUpstreamTimeout = 10 * time.Second
+
 // PokeAPIService represents the service for integrating with the PokeAPI
 type PokeAPIService struct {
 	baseURL    string
 	httpClient *http.Client
+	timeout    time.Duration
+	retry      RetryPolicy
+	breaker    *CircuitBreaker
+	limiter    *RateLimiter
+	flights    flightGroup
+	store      store.Store
+	offline    bool
+	ids        *PokemonIDs
 }
 
 // NewPokeAPIService creates a new instance of the service
 func NewPokeAPIService(cfg *config.Config) *PokeAPIService {
+	timeout := cfg.UpstreamTimeout
+	if timeout <= 0 {
+		timeout = defaultUpstreamTimeout
+	}
+
+	// A threshold of zero disables the circuit breaker
+	var breaker *CircuitBreaker
+	if cfg.BreakerFailureThreshold > 0 {
+		breaker = NewCircuitBreaker(cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout, cfg.BreakerHalfOpenRequests)
+	}
+
+	// A rate of zero disables the outbound rate limiter
+	var limiter *RateLimiter
+	if cfg.UpstreamRateLimit > 0 {
+		limiter = NewRateLimiter(cfg.UpstreamRateLimit, cfg.UpstreamRateBurst, cfg.UpstreamRateMaxWait)
+	}
+
 	return &PokeAPIService{
-		baseURL: cfg.PokeAPIBaseURL,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		baseURL:    cfg.PokeAPIBaseURL,
+		httpClient: &http.Client{},
+		timeout:    timeout,
+		retry:      NewRetryPolicy(cfg),
+		breaker:    breaker,
+		limiter:    limiter,
+		offline:    cfg.Offline,
+		ids:        NewPokemonIDs(MaxPokemonID),
 	}
 }
 
+// WithStore sets the local store where raw payloads are persisted. In offline
+// mode the store is the only source of data.
+func (s *PokeAPIService) WithStore(st store.Store) *PokeAPIService {
+	s.store = st
+	return s
+}
+
 // GetPokemonByID searches for a Pokémon by ID
-func (s *PokeAPIService) GetPokemonByID(id int) (*types.Pokemon, error) {
-	url := fmt.Sprintf("%s/pokemon/%d", s.baseURL, id)
-	
-	resp, err := s.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer resp.Body.Close()
+func (s *PokeAPIService) GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error) {
+	return s.getPokemon(ctx, fmt.Sprintf("/pokemon/%d", id))
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: status %d", resp.StatusCode)
-	}
+// GetPokemonByName searches for a Pokémon by name
+func (s *PokeAPIService) GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error) {
+	return s.getPokemon(ctx, "/pokemon/"+strings.ToLower(name))
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getPokemon fetches and decodes the Pokémon at the given path
+func (s *PokeAPIService) getPokemon(ctx context.Context, path string) (*types.Pokemon, error) {
+	body, err := s.fetch(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return nil, err
 	}
 
 	var pokemon types.Pokemon
 	if err := json.Unmarshal(body, &pokemon); err != nil {
-		return nil, fmt.Errorf("error deserializing JSON: %w", err)
+		return nil, &UpstreamError{Path: path, Kind: ErrDecode, Cause: err}
 	}
 
+	// Keep a single copy per Pokémon, reachable by ID and by name
+	canonical := fmt.Sprintf("/pokemon/%d", pokemon.ID)
+	s.persist(canonical, body, path, "/pokemon/"+strings.ToLower(pokemon.Name))
+
 	return &pokemon, nil
 }
 
-// GetPokemonByName searches for a Pokémon by name
-func (s *PokeAPIService) GetPokemonByName(name string) (*types.Pokemon, error) {
-	url := fmt.Sprintf("%s/pokemon/%s", s.baseURL, strings.ToLower(name))
-	
-	resp, err := s.httpClient.Get(url)
+// fetchResource fetches the resource at the given path and persists it to the local store
+func (s *PokeAPIService) fetchResource(ctx context.Context, path string) ([]byte, error) {
+	body, err := s.fetch(ctx, path)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, err
+	}
+
+	s.persist(path, body)
+	return body, nil
+}
+
+// fetch returns the raw body of the resource at the given path. Concurrent
+// requests for the same path share a single upstream call, which is retried
+// according to the retry policy and stops as soon as every caller has gone.
+// In offline mode the body is read from the local store instead.
+func (s *PokeAPIService) fetch(ctx context.Context, path string) ([]byte, error) {
+	if s.offline {
+		body, err := s.readStore(path)
+		if err == nil {
+			origin.Record(ctx, origin.SourceStore)
+		}
+		return body, err
+	}
+
+	result, err := s.flights.do(ctx, path, func(ctx context.Context) (fetched, error) {
+		body, err := s.callUpstream(ctx, path)
+
+		// Serve the last known payload while the PokeAPI is unavailable
+		if errors.Is(err, ErrUpstreamUnavailable) && s.store != nil {
+			if stored, storeErr := s.readStore(path); storeErr == nil {
+				log.Printf("PokeAPI unavailable, serving %s from the local store: %v", path, err)
+				return fetched{body: stored, source: origin.SourceStore}, nil
+			}
+		}
+
+		return fetched{body: body, source: origin.SourcePokeAPI}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	origin.Record(ctx, result.source)
+	return result.body, nil
+}
+
+// callUpstream requests the path from the PokeAPI through the circuit breaker and the retry policy
+func (s *PokeAPIService) callUpstream(ctx context.Context, path string) ([]byte, error) {
+	if s.breaker != nil {
+		if err := s.breaker.allow(); err != nil {
+			return nil, &UpstreamError{Path: path, Kind: ErrUpstreamUnavailable, Cause: err}
+		}
+	}
+
+	body, err := s.retry.do(ctx, func() ([]byte, error) {
+		return s.request(ctx, path)
+	})
+
+	if s.breaker != nil {
+		s.breaker.record(err)
+	}
+	return body, err
+}
+
+// UpstreamHealth returns the state of the connection to the PokeAPI
+func (s *PokeAPIService) UpstreamHealth() types.UpstreamHealth {
+	var health types.UpstreamHealth
+	if s.breaker != nil {
+		status := s.breaker.Status()
+		health.CircuitBreaker = &status
+	}
+	if s.limiter != nil {
+		status := s.limiter.Status()
+		health.RateLimiter = &status
+	}
+	return health
+}
+
+// request performs a single GET request to the PokeAPI once the rate limiter
+// allows it, bounded by the upstream timeout
+func (s *PokeAPIService) request(ctx context.Context, path string) ([]byte, error) {
+	if s.limiter != nil {
+		if err := s.limiter.wait(ctx); errors.Is(err, ErrThrottled) {
+			return nil, &UpstreamError{Path: path, Kind: ErrRateLimited, Cause: err}
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, &UpstreamError{Path: path, Kind: ErrUpstreamUnavailable, Cause: err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: status %d", resp.StatusCode)
+		return nil, errorForResponse(path, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return nil, &UpstreamError{Path: path, StatusCode: resp.StatusCode, Kind: ErrUpstreamUnavailable, Cause: err}
 	}
 
-	var pokemon types.Pokemon
-	if err := json.Unmarshal(body, &pokemon); err != nil {
-		return nil, fmt.Errorf("error deserializing JSON: %w", err)
+	return body, nil
+}
+
+// readStore returns the payload stored under the path, following name aliases
+func (s *PokeAPIService) readStore(path string) ([]byte, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("offline mode: no local store configured: %w", ErrUpstreamUnavailable)
 	}
 
-	return &pokemon, nil
+	body, err := s.store.Get(storeKey(path))
+	if errors.Is(err, store.ErrNotFound) {
+		var target []byte
+		if target, err = s.store.Get(aliasKey(path)); err == nil {
+			body, err = s.store.Get(string(target))
+		}
+	}
+
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, fmt.Errorf("offline mode: %s is not in the local store: %w", path, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading local store: %v: %w", err, ErrUpstreamUnavailable)
+	}
+
+	return body, nil
+}
+
+// persist writes the payload to the local store under the path, plus aliases
+// pointing to it. Failures are logged and never fail the request.
+func (s *PokeAPIService) persist(path string, body []byte, aliases ...string) {
+	if s.store == nil || s.offline {
+		return
+	}
+
+	key := storeKey(path)
+	if err := s.store.Put(key, body); err != nil {
+		log.Printf("Error writing %s to local store: %v", key, err)
+		return
+	}
+
+	for _, alias := range aliases {
+		if alias == path {
+			continue
+		}
+		if err := s.store.Put(aliasKey(alias), []byte(key)); err != nil {
+			log.Printf("Error writing alias %s to local store: %v", alias, err)
+		}
+	}
+}
+
+// storeKey returns the local store key for a resource path
+func storeKey(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
+
+// aliasKey returns the local store key of an alias for a resource path
+func aliasKey(path string) string {
+	return "alias/" + storeKey(path)
 }
 
 // TransformPokemonToResponse transforms the Pokémon from the API to the response format
 func (s *PokeAPIService) TransformPokemonToResponse(pokemon *types.Pokemon) *types.PokemonResponse {
+	return TransformPokemonToResponse(pokemon)
+}
+
+// TransformPokemonToResponse transforms the Pokémon from the API to the response format
+func TransformPokemonToResponse(pokemon *types.Pokemon) *types.PokemonResponse {
 	// Extract types
 	pokemonTypes := make([]string, len(pokemon.Types))
 	for i, t := range pokemon.Types {
@@ -130,14 +328,5 @@ func (s *PokeAPIService) TransformPokemonToResponse(pokemon *types.Pokemon) *typ
 
 // ValidatePokemonID validates if the Pokémon ID is valid
 func (s *PokeAPIService) ValidatePokemonID(idStr string) (int, error) {
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		return 0, fmt.Errorf("invalid ID: %s", idStr)
-	}
-
-	if id < 1 || id > 1025 { // Current limit of the PokeAPI
-		return 0, fmt.Errorf("ID must be between 1 and 1025")
-	}
-
-	return id, nil
-} 
\ No newline at end of file
+	return s.ids.Validate(idStr)
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -45,6 +47,8 @@ func TestValidatePokemonID(t *testing.T) {
 		{"invalid ID - zero", "0", 0, true},
 		{"invalid ID - negative", "-1", 0, true},
 		{"invalid ID - too high", "1026", 0, true},
+		{"form ID", "10001", 10001, false},
+		{"invalid ID - between species and forms", "10000", 0, true},
 		{"invalid ID - not number", "abc", 0, true},
 		{"invalid ID - empty", "", 0, true},
 	}
@@ -58,6 +62,10 @@ func TestValidatePokemonID(t *testing.T) {
 				return
 			}
 
+			if err != nil && !errors.Is(err, ErrInvalidID) {
+				t.Errorf("ValidatePokemonID() error = %v, want ErrInvalidID", err)
+			}
+
 			if gotID != tt.wantID {
 				t.Errorf("ValidatePokemonID() = %v, want %v", gotID, tt.wantID)
 			}
@@ -125,7 +133,7 @@ func TestGetPokemonByID_Success(t *testing.T) {
 	cfg := &config.Config{PokeAPIBaseURL: server.URL}
 	service := NewPokeAPIService(cfg)
 
-	pokemon, err := service.GetPokemonByID(25)
+	pokemon, err := service.GetPokemonByID(context.Background(), 25)
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -162,7 +170,7 @@ func TestGetPokemonByID_NotFound(t *testing.T) {
 	cfg := &config.Config{PokeAPIBaseURL: server.URL}
 	service := NewPokeAPIService(cfg)
 
-	pokemon, err := service.GetPokemonByID(99999)
+	pokemon, err := service.GetPokemonByID(context.Background(), 99999)
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pokedexia-backend/internal/config"
+)
+
+// RetryPolicy represents how failed PokeAPI requests are retried
+type RetryPolicy struct {
+	MaxAttempts     int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	Jitter          float64 // Fraction of the delay randomly added or removed, between 0 and 1
+	RetryableStatus []int
+}
+
+// NewRetryPolicy creates the retry policy from the configuration
+func NewRetryPolicy(cfg *config.Config) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     cfg.RetryMaxAttempts,
+		BaseDelay:       cfg.RetryBaseDelay,
+		MaxDelay:        cfg.RetryMaxDelay,
+		Jitter:          cfg.RetryJitter,
+		RetryableStatus: cfg.RetryStatusCodes,
+	}
+}
+
+// do calls fn until it succeeds, fails with an error that is not retryable,
+// or the attempts run out. The wait between attempts grows exponentially and
+// honors the Retry-After header sent by the PokeAPI.
+func (p RetryPolicy) do(ctx context.Context, fn func() ([]byte, error)) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(ctx, err) {
+			return body, err
+		}
+
+		var retryAfter time.Duration
+		var upstreamErr *UpstreamError
+		if errors.As(err, &upstreamErr) {
+			retryAfter = upstreamErr.RetryAfter
+		}
+
+		// Waiting longer than the policy allows is the same as giving up
+		delay := p.delay(attempt, retryAfter)
+		if delay < 0 {
+			return body, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether the request that failed with err should be attempted again
+func (p RetryPolicy) retryable(ctx context.Context, err error) bool {
+	// Asking again right away would only queue behind the same rate limiter
+	if ctx.Err() != nil || errors.Is(err, ErrThrottled) {
+		return false
+	}
+
+	var upstreamErr *UpstreamError
+	if !errors.As(err, &upstreamErr) || !errors.Is(err, ErrUpstreamUnavailable) && !errors.Is(err, ErrRateLimited) {
+		return false
+	}
+
+	// Connection errors and resets have no status code
+	if upstreamErr.StatusCode == 0 {
+		return true
+	}
+
+	// A missing Pokémon will not appear by asking again
+	if upstreamErr.StatusCode == http.StatusNotFound {
+		return false
+	}
+
+	for _, status := range p.RetryableStatus {
+		if status == upstreamErr.StatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the wait before the next attempt, or a negative duration if
+// the server asked to wait longer than the maximum delay
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return -1
+		}
+		return retryAfter
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	return time.Duration(delay)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
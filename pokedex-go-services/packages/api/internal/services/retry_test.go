@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pokedexia-backend/internal/config"
+)
+
+// newFlakyServer returns a server that fails the first failures requests with
+// the given status and then answers with a Pokémon
+func newFlakyServer(failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{"id": 25, "name": "pikachu"}`))
+	}))
+	return server, &hits
+}
+
+func newRetryConfig(url string) *config.Config {
+	return &config.Config{
+		PokeAPIBaseURL:   url,
+		RetryMaxAttempts: 3,
+		RetryBaseDelay:   time.Millisecond,
+		RetryMaxDelay:    10 * time.Millisecond,
+		RetryJitter:      0.5,
+		RetryStatusCodes: []int{429, 500, 502, 503, 504},
+	}
+}
+
+func TestRetry_RecoversFromTransientErrors(t *testing.T) {
+	server, hits := newFlakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	service := NewPokeAPIService(newRetryConfig(server.URL))
+
+	pokemon, err := service.GetPokemonByID(context.Background(), 25)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if pokemon.Name != "pikachu" {
+		t.Errorf("Expected name 'pikachu', got %s", pokemon.Name)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	server, hits := newFlakyServer(10, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	service := NewPokeAPIService(newRetryConfig(server.URL))
+
+	_, err := service.GetPokemonByID(context.Background(), 25)
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if got := hits.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestRetry_NeverRetriesNotFound(t *testing.T) {
+	server, hits := newFlakyServer(10, http.StatusNotFound, nil)
+	defer server.Close()
+
+	// Even a misconfigured policy must not retry a 404
+	cfg := newRetryConfig(server.URL)
+	cfg.RetryStatusCodes = append(cfg.RetryStatusCodes, http.StatusNotFound)
+	service := NewPokeAPIService(cfg)
+
+	_, err := service.GetPokemonByID(context.Background(), 25)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetry_SkipsStatusesNotInPolicy(t *testing.T) {
+	server, hits := newFlakyServer(10, http.StatusInternalServerError, nil)
+	defer server.Close()
+
+	cfg := newRetryConfig(server.URL)
+	cfg.RetryStatusCodes = []int{503}
+	service := NewPokeAPIService(cfg)
+
+	service.GetPokemonByID(context.Background(), 25)
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetry_HonorsRetryAfter(t *testing.T) {
+	server, hits := newFlakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	defer server.Close()
+
+	cfg := newRetryConfig(server.URL)
+	cfg.RetryMaxDelay = 2 * time.Second
+	service := NewPokeAPIService(cfg)
+
+	start := time.Now()
+	if _, err := service.GetPokemonByID(context.Background(), 25); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, waited %v", elapsed)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetry_RetryAfterBeyondMaxDelay(t *testing.T) {
+	server, hits := newFlakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"120"}})
+	defer server.Close()
+
+	service := NewPokeAPIService(newRetryConfig(server.URL))
+
+	_, err := service.GetPokemonByID(context.Background(), 25)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter != 2*time.Minute {
+		t.Errorf("Expected RetryAfter of 2m, got %v", upstreamErr.RetryAfter)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{1, 0, 100 * time.Millisecond},
+		{2, 0, 200 * time.Millisecond},
+		{3, 0, 400 * time.Millisecond},
+		{5, 0, time.Second},
+		{1, 500 * time.Millisecond, 500 * time.Millisecond},
+		{1, 2 * time.Second, -1},
+	}
+
+	for _, tt := range tests {
+		if got := policy.delay(tt.attempt, tt.retryAfter); got != tt.want {
+			t.Errorf("delay(%d, %v) = %v, want %v", tt.attempt, tt.retryAfter, got, tt.want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.delay(2, 0); got < 100*time.Millisecond || got > 300*time.Millisecond {
+			t.Fatalf("Expected jittered delay between 100ms and 300ms, got %v", got)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"pokedexia-backend/internal/types"
+)
+
+// PokemonSource represents any provider of Pokémon data used by the handlers
+type PokemonSource interface {
+	GetPokemonByID(ctx context.Context, id int) (*types.Pokemon, error)
+	GetPokemonByName(ctx context.Context, name string) (*types.Pokemon, error)
+	GetSpecies(ctx context.Context, id int) (*types.PokemonSpecies, error)
+	GetEvolutionChain(ctx context.Context, id int) (*types.EvolutionChain, error)
+	GetMove(ctx context.Context, id int) (*types.Move, error)
+	TransformPokemonToResponse(pokemon *types.Pokemon) *types.PokemonResponse
+}
+
+// HealthReporter is implemented by sources that can report the state of their upstream
+type HealthReporter interface {
+	UpstreamHealth() types.UpstreamHealth
+}
+
+// Ensure the PokeAPI service satisfies the interfaces
+var (
+	_ PokemonSource  = (*PokeAPIService)(nil)
+	_ HealthReporter = (*PokeAPIService)(nil)
+)
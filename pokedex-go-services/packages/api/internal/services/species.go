@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"pokedexia-backend/internal/i18n"
+	"pokedexia-backend/internal/types"
+)
+
+// speciesListPath lists every species in a single page
+const speciesListPath = "/pokemon-species?limit=100000"
+
+// genderNames maps the PokeAPI gender IDs used in evolution conditions
+var genderNames = map[int]string{1: "female", 2: "male"}
+
+// GetSpecies searches for a Pokémon species by ID
+func (s *PokeAPIService) GetSpecies(ctx context.Context, id int) (*types.PokemonSpecies, error) {
+	path := fmt.Sprintf("/pokemon-species/%d", id)
+
+	body, err := s.fetchResource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var species types.PokemonSpecies
+	if err := json.Unmarshal(body, &species); err != nil {
+		return nil, &UpstreamError{Path: path, Kind: ErrDecode, Cause: err}
+	}
+
+	return &species, nil
+}
+
+// ListSpecies returns a reference to every Pokémon species
+func (s *PokeAPIService) ListSpecies(ctx context.Context) ([]types.NamedResource, error) {
+	list, err := s.fetchList(ctx, speciesListPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Results, nil
+}
+
+// GetEvolutionChain searches for an evolution chain by ID
+func (s *PokeAPIService) GetEvolutionChain(ctx context.Context, id int) (*types.EvolutionChain, error) {
+	path := fmt.Sprintf("/evolution-chain/%d", id)
+
+	body, err := s.fetchResource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain types.EvolutionChain
+	if err := json.Unmarshal(body, &chain); err != nil {
+		return nil, &UpstreamError{Path: path, Kind: ErrDecode, Cause: err}
+	}
+
+	return &chain, nil
+}
+
+// ResourceID returns the numeric ID at the end of a PokeAPI resource URL, or
+// zero if there is none
+func ResourceID(url string) int {
+	url = strings.TrimSuffix(url, "/")
+	id, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// TransformSpeciesToResponse transforms the species from the API to the response format
+func TransformSpeciesToResponse(species *types.PokemonSpecies) *types.SpeciesResponse {
+	response := &types.SpeciesResponse{
+		ID:          species.ID,
+		Name:        species.Name,
+		Generation:  species.Generation.Name,
+		IsBaby:      species.IsBaby,
+		IsLegendary: species.IsLegendary,
+		IsMythical:  species.IsMythical,
+		Names:       make(map[string]string),
+		Genus:       make(map[string]string),
+		FlavorText:  make(map[string][]types.FlavorText),
+	}
+
+	for _, name := range species.Names {
+		response.Names[name.Language.Name] = name.Name
+	}
+
+	if species.EvolvesFromSpecies != nil {
+		response.EvolvesFrom = species.EvolvesFromSpecies.Name
+	}
+
+	for _, genus := range species.Genera {
+		response.Genus[genus.Language.Name] = genus.Genus
+	}
+
+	for _, entry := range species.FlavorTextEntries {
+		lang := entry.Language.Name
+		response.FlavorText[lang] = append(response.FlavorText[lang], types.FlavorText{
+			Version: entry.Version.Name,
+			Text:    cleanFlavorText(entry.FlavorText),
+		})
+	}
+
+	return response
+}
+
+// LocalizeSpecies fills in the species texts in the given PokeAPI language
+func LocalizeSpecies(response *types.SpeciesResponse, lang string) {
+	localized := &types.LocalizedSpecies{
+		Name:       response.Name,
+		FlavorText: []types.FlavorText{},
+	}
+	if name, ok := i18n.Select(response.Names, lang); ok {
+		localized.Name = name
+	}
+	localized.Genus, _ = i18n.Select(response.Genus, lang)
+	if entries, ok := i18n.Select(response.FlavorText, lang); ok {
+		localized.FlavorText = entries
+	}
+
+	response.Language = lang
+	response.Localized = localized
+}
+
+// TransformEvolutionChainToResponse transforms the evolution chain from the API to the response format
+func TransformEvolutionChainToResponse(chain *types.EvolutionChain) *types.EvolutionChainResponse {
+	return &types.EvolutionChainResponse{
+		ID:    chain.ID,
+		Chain: transformChainLink(chain.Chain),
+	}
+}
+
+// transformChainLink resolves a link of the evolution chain and everything it evolves to
+func transformChainLink(link types.ChainLink) types.EvolutionNode {
+	node := types.EvolutionNode{
+		SpeciesID: ResourceID(link.Species.URL),
+		Species:   link.Species.Name,
+		IsBaby:    link.IsBaby,
+		EvolvesTo: make([]types.EvolutionNode, 0, len(link.EvolvesTo)),
+	}
+
+	for _, detail := range link.EvolutionDetails {
+		node.Conditions = append(node.Conditions, transformEvolutionDetail(detail))
+	}
+
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, transformChainLink(next))
+	}
+
+	return node
+}
+
+// transformEvolutionDetail keeps only the requirements that apply to the evolution
+func transformEvolutionDetail(detail types.EvolutionDetail) types.EvolutionCondition {
+	return types.EvolutionCondition{
+		Trigger:               detail.Trigger.Name,
+		MinLevel:              detail.MinLevel,
+		Item:                  resourceName(detail.Item),
+		HeldItem:              resourceName(detail.HeldItem),
+		KnownMove:             resourceName(detail.KnownMove),
+		KnownMoveType:         resourceName(detail.KnownMoveType),
+		Location:              resourceName(detail.Location),
+		PartySpecies:          resourceName(detail.PartySpecies),
+		PartyType:             resourceName(detail.PartyType),
+		TradeSpecies:          resourceName(detail.TradeSpecies),
+		Gender:                genderName(detail.Gender),
+		MinHappiness:          detail.MinHappiness,
+		MinBeauty:             detail.MinBeauty,
+		MinAffection:          detail.MinAffection,
+		RelativePhysicalStats: detail.RelativePhysicalStats,
+		TimeOfDay:             detail.TimeOfDay,
+		NeedsOverworldRain:    detail.NeedsOverworldRain,
+		TurnUpsideDown:        detail.TurnUpsideDown,
+	}
+}
+
+// resourceName returns the name of an optional resource
+func resourceName(resource *types.NamedResource) string {
+	if resource == nil {
+		return ""
+	}
+	return resource.Name
+}
+
+// genderName returns the name of an optional gender ID
+func genderName(gender *int) string {
+	if gender == nil {
+		return ""
+	}
+	return genderNames[*gender]
+}
+
+// cleanFlavorText removes the line breaks and page feeds the games use for layout
+func cleanFlavorText(text string) string {
+	text = strings.ReplaceAll(text, "­\n", "")
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+)
+
+const speciesBody = `{
+	"id": 133,
+	"name": "eevee",
+	"is_baby": false,
+	"is_legendary": false,
+	"is_mythical": false,
+	"generation": {"name": "generation-i", "url": "https://pokeapi.co/api/v2/generation/1/"},
+	"names": [
+		{"name": "Eevee", "language": {"name": "en", "url": ""}},
+		{"name": "イーブイ", "language": {"name": "ja-Hrkt", "url": ""}},
+		{"name": "Évoli", "language": {"name": "fr", "url": ""}}
+	],
+	"genera": [
+		{"genus": "Evolution Pokémon", "language": {"name": "en", "url": ""}},
+		{"genus": "Pokémon Evolución", "language": {"name": "es", "url": ""}}
+	],
+	"flavor_text_entries": [
+		{"flavor_text": "Its genetic code is\nirregular. It may mu­\ntate if it is\fexposed to radiation.", "language": {"name": "en", "url": ""}, "version": {"name": "red", "url": ""}}
+	],
+	"evolves_from_species": null,
+	"evolution_chain": {"url": "https://pokeapi.co/api/v2/evolution-chain/67/"}
+}`
+
+const evolutionChainBody = `{
+	"id": 67,
+	"chain": {
+		"is_baby": false,
+		"species": {"name": "eevee", "url": "https://pokeapi.co/api/v2/pokemon-species/133/"},
+		"evolution_details": [],
+		"evolves_to": [
+			{
+				"is_baby": false,
+				"species": {"name": "vaporeon", "url": "https://pokeapi.co/api/v2/pokemon-species/134/"},
+				"evolution_details": [{"trigger": {"name": "use-item", "url": ""}, "item": {"name": "water-stone", "url": ""}, "min_level": null, "gender": null}],
+				"evolves_to": []
+			},
+			{
+				"is_baby": false,
+				"species": {"name": "umbreon", "url": "https://pokeapi.co/api/v2/pokemon-species/197/"},
+				"evolution_details": [{"trigger": {"name": "level-up", "url": ""}, "min_happiness": 160, "time_of_day": "night", "gender": 2}],
+				"evolves_to": []
+			}
+		]
+	}
+}`
+
+func newSpeciesServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pokemon-species/133":
+			w.Write([]byte(speciesBody))
+		case "/evolution-chain/67":
+			w.Write([]byte(evolutionChainBody))
+		case "/pokemon-species":
+			w.Write([]byte(`{"count": 2, "results": [
+				{"name": "bulbasaur", "url": "https://pokeapi.co/api/v2/pokemon-species/1/"},
+				{"name": "ivysaur", "url": "https://pokeapi.co/api/v2/pokemon-species/2/"}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGetSpecies(t *testing.T) {
+	server := newSpeciesServer()
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	species, err := service.GetSpecies(context.Background(), 133)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := TransformSpeciesToResponse(species)
+
+	if response.Generation != "generation-i" {
+		t.Errorf("Expected generation-i, got %s", response.Generation)
+	}
+	if response.Genus["en"] != "Evolution Pokémon" || response.Genus["es"] != "Pokémon Evolución" {
+		t.Errorf("Unexpected genus %v", response.Genus)
+	}
+	if response.EvolvesFrom != "" {
+		t.Errorf("Expected no pre-evolution, got %s", response.EvolvesFrom)
+	}
+
+	entries := response.FlavorText["en"]
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 english flavor text, got %d", len(entries))
+	}
+	expected := "Its genetic code is irregular. It may mutate if it is exposed to radiation."
+	if entries[0].Version != "red" || entries[0].Text != expected {
+		t.Errorf("Unexpected flavor text %+v", entries[0])
+	}
+
+	if _, err := service.GetSpecies(context.Background(), 99999); err == nil {
+		t.Error("Expected an error for an unknown species")
+	}
+}
+
+func TestLocalizeSpecies(t *testing.T) {
+	server := newSpeciesServer()
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	species, err := service.GetSpecies(context.Background(), 133)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := TransformSpeciesToResponse(species)
+	if response.Names["fr"] != "Évoli" {
+		t.Errorf("Expected names by language, got %v", response.Names)
+	}
+
+	LocalizeSpecies(response, "es")
+	if response.Language != "es" || response.Localized.Genus != "Pokémon Evolución" {
+		t.Errorf("Expected the Spanish genus, got %+v", response.Localized)
+	}
+	// No Spanish name or flavor text: English is used
+	if response.Localized.Name != "Eevee" || len(response.Localized.FlavorText) != 1 {
+		t.Errorf("Expected English fallbacks, got %+v", response.Localized)
+	}
+
+	LocalizeSpecies(response, "ja")
+	if response.Localized.Name != "イーブイ" {
+		t.Errorf("Expected the kana name, got %s", response.Localized.Name)
+	}
+}
+
+func TestGetEvolutionChain(t *testing.T) {
+	server := newSpeciesServer()
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	chain, err := service.GetEvolutionChain(context.Background(), 67)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := TransformEvolutionChainToResponse(chain)
+
+	root := response.Chain
+	if root.SpeciesID != 133 || root.Species != "eevee" || len(root.Conditions) != 0 {
+		t.Errorf("Unexpected root %+v", root)
+	}
+	if len(root.EvolvesTo) != 2 {
+		t.Fatalf("Expected 2 evolutions, got %d", len(root.EvolvesTo))
+	}
+
+	vaporeon := root.EvolvesTo[0]
+	if vaporeon.SpeciesID != 134 || len(vaporeon.Conditions) != 1 {
+		t.Fatalf("Unexpected node %+v", vaporeon)
+	}
+	if c := vaporeon.Conditions[0]; c.Trigger != "use-item" || c.Item != "water-stone" || c.MinLevel != nil {
+		t.Errorf("Unexpected condition %+v", c)
+	}
+
+	umbreon := root.EvolvesTo[1]
+	if len(umbreon.Conditions) != 1 {
+		t.Fatalf("Expected 1 condition, got %d", len(umbreon.Conditions))
+	}
+	c := umbreon.Conditions[0]
+	if c.Trigger != "level-up" || c.TimeOfDay != "night" || c.Gender != "male" {
+		t.Errorf("Unexpected condition %+v", c)
+	}
+	if c.MinHappiness == nil || *c.MinHappiness != 160 {
+		t.Errorf("Expected min happiness 160, got %v", c.MinHappiness)
+	}
+}
+
+func TestListSpecies(t *testing.T) {
+	server := newSpeciesServer()
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL})
+
+	species, err := service.ListSpecies(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(species) != 2 || species[1].Name != "ivysaur" || ResourceID(species[1].URL) != 2 {
+		t.Errorf("Unexpected species list %+v", species)
+	}
+}
+
+func TestResourceID(t *testing.T) {
+	tests := map[string]int{
+		"https://pokeapi.co/api/v2/pokemon-species/133/": 133,
+		"https://pokeapi.co/api/v2/evolution-chain/67":   67,
+		"https://pokeapi.co/api/v2/pokemon-species/":     0,
+		"": 0,
+	}
+
+	for url, expected := range tests {
+		if got := ResourceID(url); got != expected {
+			t.Errorf("ResourceID(%q) = %d, expected %d", url, got, expected)
+		}
+	}
+}
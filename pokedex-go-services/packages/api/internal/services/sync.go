@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"pokedexia-backend/internal/types"
+)
+
+// SyncOptions represents the settings of a bulk sync of the Pokédex. IDs
+// lists the Pokémon to sync; without it, the range From-To is synced.
+type SyncOptions struct {
+	IDs           []int
+	From          int
+	To            int
+	Concurrency   int
+	RatePerSecond float64
+	Force         bool
+}
+
+// SyncProgress represents the state of a sync after each processed Pokémon
+type SyncProgress struct {
+	ID      int
+	Total   int
+	Done    int
+	Fetched int
+	Skipped int
+	Failed  int
+	Err     error
+}
+
+// Sync mirrors the Pokémon with the given IDs and their species into the
+// local store, along with the listings read at startup, so the store is
+// enough to run offline. Pokémon that are already stored are skipped unless
+// Force is set, so an interrupted sync resumes where it stopped. The progress
+// callback is called once per Pokémon and may be nil.
+func (s *PokeAPIService) Sync(ctx context.Context, opts SyncOptions, progress func(SyncProgress)) (SyncProgress, error) {
+	if s.store == nil {
+		return SyncProgress{}, errors.New("sync requires a local store")
+	}
+	if s.offline {
+		return SyncProgress{}, errors.New("sync is not available in offline mode")
+	}
+	if len(opts.IDs) == 0 {
+		if opts.From < 1 || opts.To < opts.From {
+			return SyncProgress{}, fmt.Errorf("invalid ID range %d-%d", opts.From, opts.To)
+		}
+		for id := opts.From; id <= opts.To; id++ {
+			opts.IDs = append(opts.IDs, id)
+		}
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+
+	limit := rate.Inf
+	if opts.RatePerSecond > 0 {
+		limit = rate.Limit(opts.RatePerSecond)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	if err := s.syncLists(ctx, limiter); err != nil {
+		return SyncProgress{}, err
+	}
+
+	ids := make(chan int)
+	go func() {
+		defer close(ids)
+		for _, id := range opts.IDs {
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var mu sync.Mutex
+	state := SyncProgress{Total: len(opts.IDs)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				skipped, err := s.syncPokemon(ctx, limiter, id, opts.Force)
+
+				mu.Lock()
+				state.ID, state.Err = id, err
+				state.Done++
+				switch {
+				case err != nil:
+					state.Failed++
+				case skipped:
+					state.Skipped++
+				default:
+					state.Fetched++
+				}
+				if progress != nil {
+					progress(state)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	state.ID, state.Err = 0, nil
+	return state, ctx.Err()
+}
+
+// syncLists stores the listings the service reads at startup: the pages
+// RefreshIDs discovers the valid IDs from and the list of every species.
+// They are always fetched again, since they grow with new generations.
+func (s *PokeAPIService) syncLists(ctx context.Context, limiter *rate.Limiter) error {
+	for _, path := range []string{speciesCountPath, pokemonListPath, speciesListPath} {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if _, err := s.fetchResource(ctx, path); err != nil {
+			return fmt.Errorf("listing %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// syncPokemon stores the Pokémon and species payloads for the ID, reporting
+// whether both were already present. Alternate forms share the species of
+// their base Pokémon.
+func (s *PokeAPIService) syncPokemon(ctx context.Context, limiter *rate.Limiter, id int, force bool) (bool, error) {
+	pokemonPath := fmt.Sprintf("/pokemon/%d", id)
+
+	if !force {
+		if body, err := s.readStore(pokemonPath); err == nil {
+			var pokemon types.Pokemon
+			if json.Unmarshal(body, &pokemon) == nil && s.stored(speciesPath(&pokemon)) {
+				return true, nil
+			}
+		}
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return false, err
+	}
+	pokemon, err := s.getPokemon(ctx, pokemonPath)
+	if err != nil {
+		return false, fmt.Errorf("pokemon %d: %w", id, err)
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return false, err
+	}
+	if _, err := s.fetchResource(ctx, speciesPath(pokemon)); err != nil {
+		return false, fmt.Errorf("species of pokemon %d: %w", id, err)
+	}
+
+	return false, nil
+}
+
+// speciesPath returns the path of the species of the Pokémon, which has the
+// same ID unless the Pokémon is an alternate form
+func speciesPath(pokemon *types.Pokemon) string {
+	id := ResourceID(pokemon.Species.URL)
+	if id == 0 {
+		id = pokemon.ID
+	}
+	return fmt.Sprintf("/pokemon-species/%d", id)
+}
+
+// stored reports whether the payload for the path is already in the local store
+func (s *PokeAPIService) stored(path string) bool {
+	_, err := s.store.Get(storeKey(path))
+	return err == nil
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"pokedexia-backend/internal/config"
+)
+
+func newSyncServer(t *testing.T, failID int) (*httptest.Server, *atomic.Int32) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+
+		// Listings of the species and of every Pokémon
+		if r.URL.Query().Get("limit") != "" {
+			fmt.Fprint(w, `{"count": 5, "results": [
+				{"name": "pokemon-1", "url": "https://pokeapi.co/api/v2/pokemon/1/"},
+				{"name": "pokemon-10001", "url": "https://pokeapi.co/api/v2/pokemon/10001/"}]}`)
+			return
+		}
+
+		var id int
+		var resource string
+		if _, err := fmt.Sscanf(strings.ReplaceAll(r.URL.Path, "/", " "), " %s %d", &resource, &id); err != nil {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if id == failID {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d, "name": "pokemon-%d"}`, id, id)
+	}))
+	return server, &hits
+}
+
+func TestSync(t *testing.T) {
+	server, hits := newSyncServer(t, 0)
+	defer server.Close()
+
+	st := newMapStore()
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(st)
+
+	var calls int
+	result, err := service.Sync(context.Background(), SyncOptions{From: 1, To: 5, Concurrency: 3}, func(SyncProgress) {
+		calls++
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Fetched != 5 || result.Skipped != 0 || result.Failed != 0 {
+		t.Errorf("Expected 5 fetched, got %+v", result)
+	}
+	if calls != 5 {
+		t.Errorf("Expected 5 progress callbacks, got %d", calls)
+	}
+	// Two per Pokémon, plus the three listings
+	if got := hits.Load(); got != 13 {
+		t.Errorf("Expected 13 upstream requests, got %d", got)
+	}
+
+	for id := 1; id <= 5; id++ {
+		if _, err := st.Get(fmt.Sprintf("pokemon/%d", id)); err != nil {
+			t.Errorf("Expected pokemon %d in store, got %v", id, err)
+		}
+		if _, err := st.Get(fmt.Sprintf("pokemon-species/%d", id)); err != nil {
+			t.Errorf("Expected species %d in store, got %v", id, err)
+		}
+	}
+}
+
+func TestSync_Offline(t *testing.T) {
+	server, _ := newSyncServer(t, 0)
+	defer server.Close()
+
+	st := newMapStore()
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(st)
+	if _, err := service.Sync(context.Background(), SyncOptions{From: 1, To: 2}, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A bounded sync still stores what the service loads at startup
+	offline := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL, Offline: true}).WithStore(st)
+
+	species, err := offline.ListSpecies(context.Background())
+	if err != nil || len(species) != 2 {
+		t.Errorf("Expected the species list from the store, got %v, %v", species, err)
+	}
+	if err := offline.RefreshIDs(context.Background()); err != nil {
+		t.Fatalf("Expected the IDs from the store, got %v", err)
+	}
+	if offline.IDs().Max() != 5 || !offline.IDs().Contains(10001) {
+		t.Errorf("Expected IDs 1-5 and the form 10001, got max %d", offline.IDs().Max())
+	}
+}
+
+func TestSync_Resumes(t *testing.T) {
+	server, hits := newSyncServer(t, 4)
+	defer server.Close()
+
+	st := newMapStore()
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(st)
+	opts := SyncOptions{From: 1, To: 5, Concurrency: 2}
+
+	result, _ := service.Sync(context.Background(), opts, nil)
+	if result.Fetched != 4 || result.Failed != 1 {
+		t.Fatalf("Expected 4 fetched and 1 failed, got %+v", result)
+	}
+
+	hits.Store(0)
+	result, _ = service.Sync(context.Background(), opts, nil)
+	if result.Skipped != 4 || result.Failed != 1 {
+		t.Errorf("Expected 4 skipped and 1 failed, got %+v", result)
+	}
+	if got := hits.Load(); got != 4 {
+		t.Errorf("Expected only the listings and the failed Pokémon to be requested again, got %d requests", got)
+	}
+
+	opts.Force = true
+	hits.Store(0)
+	result, _ = service.Sync(context.Background(), opts, nil)
+	if result.Skipped != 0 {
+		t.Errorf("Expected nothing skipped with Force, got %+v", result)
+	}
+}
+
+func TestSync_Validation(t *testing.T) {
+	cfg := &config.Config{PokeAPIBaseURL: "http://localhost"}
+
+	if _, err := NewPokeAPIService(cfg).Sync(context.Background(), SyncOptions{From: 1, To: 2}, nil); err == nil {
+		t.Error("Expected error without a local store, got nil")
+	}
+
+	service := NewPokeAPIService(cfg).WithStore(newMapStore())
+	if _, err := service.Sync(context.Background(), SyncOptions{From: 5, To: 2}, nil); err == nil {
+		t.Error("Expected error for an invalid range, got nil")
+	}
+}
+
+func TestSync_Cancelled(t *testing.T) {
+	server, _ := newSyncServer(t, 0)
+	defer server.Close()
+
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(newMapStore())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := service.Sync(ctx, SyncOptions{From: 1, To: 100, Concurrency: 2, RatePerSecond: 1}, nil)
+	if err == nil {
+		t.Fatal("Expected context error, got nil")
+	}
+	if result.Fetched != 0 {
+		t.Errorf("Expected nothing fetched after cancellation, got %+v", result)
+	}
+}
+
+func TestSync_Forms(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/pokemon", "/pokemon-species":
+			fmt.Fprint(w, `{"count": 386, "results": []}`)
+		case "/pokemon/10001":
+			fmt.Fprint(w, `{"id": 10001, "name": "deoxys-attack", "species": {"name": "deoxys", "url": "https://pokeapi.co/api/v2/pokemon-species/386/"}}`)
+		case "/pokemon-species/386":
+			fmt.Fprint(w, `{"id": 386, "name": "deoxys"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	st := newMapStore()
+	service := NewPokeAPIService(&config.Config{PokeAPIBaseURL: server.URL}).WithStore(st)
+	opts := SyncOptions{IDs: []int{10001}}
+
+	result, err := service.Sync(context.Background(), opts, nil)
+	if err != nil || result.Fetched != 1 || result.Total != 1 {
+		t.Fatalf("Expected the form to be fetched, got %+v, %v", result, err)
+	}
+	if _, err := st.Get("pokemon-species/386"); err != nil {
+		t.Errorf("Expected the species of the form in store, got %v", err)
+	}
+
+	// The stored form is recognized by its species
+	result, _ = service.Sync(context.Background(), opts, nil)
+	if result.Skipped != 1 || len(paths) != 8 {
+		t.Errorf("Expected the form to be skipped, got %+v after %v", result, paths)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"pokedexia-backend/internal/typechart"
+	"pokedexia-backend/internal/types"
+)
+
+// TypeMatchups computes the damage multiplier of every attacking type against
+// the Pokémon's types and notes the abilities that grant immunities
+func TypeMatchups(pokemon *types.Pokemon) (*types.WeaknessesResponse, error) {
+	defending := make([]string, 0, len(pokemon.Types))
+	for _, t := range pokemon.Types {
+		defending = append(defending, t.Type.Name)
+	}
+
+	multipliers, err := typechart.Matchups(defending...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.WeaknessesResponse{
+		PokemonID:   pokemon.ID,
+		Pokemon:     pokemon.Name,
+		Types:       defending,
+		Multipliers: multipliers,
+		Quadruple:   []string{},
+		Double:      []string{},
+		Half:        []string{},
+		Quarter:     []string{},
+		Immune:      []string{},
+	}
+
+	// Walk the types in chart order so the groups are stable
+	for _, attacking := range typechart.Types {
+		switch multipliers[attacking] {
+		case 4:
+			response.Quadruple = append(response.Quadruple, attacking)
+		case 2:
+			response.Double = append(response.Double, attacking)
+		case 0.5:
+			response.Half = append(response.Half, attacking)
+		case 0.25:
+			response.Quarter = append(response.Quarter, attacking)
+		case 0:
+			response.Immune = append(response.Immune, attacking)
+		}
+	}
+
+	for _, ability := range pokemon.Abilities {
+		immune, err := typechart.AbilityImmunities(ability.Ability.Name, defending...)
+		if err != nil {
+			return nil, err
+		}
+		if len(immune) == 0 {
+			continue
+		}
+		response.AbilityImmunities = append(response.AbilityImmunities, types.AbilityImmunity{
+			Ability:  ability.Ability.Name,
+			IsHidden: ability.IsHidden,
+			Types:    immune,
+		})
+	}
+
+	return response, nil
+}
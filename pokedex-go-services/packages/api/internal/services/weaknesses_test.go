@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"pokedexia-backend/internal/types"
+)
+
+func TestTypeMatchups(t *testing.T) {
+	pokemon := &types.Pokemon{ID: 94, Name: "gengar"}
+	pokemon.Types = make([]types.Type, 2)
+	pokemon.Types[0].Type.Name = "ghost"
+	pokemon.Types[1].Type.Name = "poison"
+	pokemon.Abilities = make([]types.Ability, 1)
+	pokemon.Abilities[0].Ability.Name = "cursed-body"
+
+	matchups, err := TypeMatchups(pokemon)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(matchups.Double, []string{"ground", "psychic", "ghost", "dark"}) {
+		t.Errorf("Unexpected 2x weaknesses %v", matchups.Double)
+	}
+	if !reflect.DeepEqual(matchups.Quarter, []string{"poison", "bug"}) {
+		t.Errorf("Unexpected 0.25x resistances %v", matchups.Quarter)
+	}
+	if !reflect.DeepEqual(matchups.Immune, []string{"normal", "fighting"}) {
+		t.Errorf("Unexpected immunities %v", matchups.Immune)
+	}
+	if len(matchups.Quadruple) != 0 {
+		t.Errorf("Expected no 4x weaknesses, got %v", matchups.Quadruple)
+	}
+	if matchups.AbilityImmunities != nil {
+		t.Errorf("Expected no ability immunities, got %v", matchups.AbilityImmunities)
+	}
+
+	// Older generations gave Gengar Levitate
+	pokemon.Abilities[0].Ability.Name = "levitate"
+
+	matchups, err = TypeMatchups(pokemon)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matchups.AbilityImmunities) != 1 || matchups.AbilityImmunities[0].Types[0] != "ground" {
+		t.Errorf("Expected levitate to be noted, got %v", matchups.AbilityImmunities)
+	}
+	if matchups.Multipliers["ground"] != 2 {
+		t.Errorf("Expected abilities not to change multipliers, got %v", matchups.Multipliers["ground"])
+	}
+}
@@ -0,0 +1,146 @@
+// Package typechart holds the type effectiveness chart used since generation VI
+// and computes how attacking types fare against single and dual types.
+package typechart
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownType is returned for names that are not one of the 18 types
+var ErrUnknownType = errors.New("unknown type")
+
+// Types lists the 18 types in PokeAPI order
+var Types = []string{
+	"normal", "fire", "water", "electric", "grass", "ice",
+	"fighting", "poison", "ground", "flying", "psychic", "bug",
+	"rock", "ghost", "dragon", "dark", "steel", "fairy",
+}
+
+// exceptions lists, for each attacking type, the defending types that do not take normal damage
+var exceptions = map[string]map[string]float64{
+	"normal":   {"rock": 0.5, "ghost": 0, "steel": 0.5},
+	"fire":     {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 2, "bug": 2, "rock": 0.5, "dragon": 0.5, "steel": 2},
+	"water":    {"fire": 2, "water": 0.5, "grass": 0.5, "ground": 2, "rock": 2, "dragon": 0.5},
+	"electric": {"water": 2, "electric": 0.5, "grass": 0.5, "ground": 0, "flying": 2, "dragon": 0.5},
+	"grass":    {"fire": 0.5, "water": 2, "grass": 0.5, "poison": 0.5, "ground": 2, "flying": 0.5, "bug": 0.5, "rock": 2, "dragon": 0.5, "steel": 0.5},
+	"ice":      {"fire": 0.5, "water": 0.5, "grass": 2, "ice": 0.5, "ground": 2, "flying": 2, "dragon": 2, "steel": 0.5},
+	"fighting": {"normal": 2, "ice": 2, "poison": 0.5, "flying": 0.5, "psychic": 0.5, "bug": 0.5, "rock": 2, "ghost": 0, "dark": 2, "steel": 2, "fairy": 0.5},
+	"poison":   {"grass": 2, "poison": 0.5, "ground": 0.5, "rock": 0.5, "ghost": 0.5, "steel": 0, "fairy": 2},
+	"ground":   {"fire": 2, "electric": 2, "grass": 0.5, "poison": 2, "flying": 0, "bug": 0.5, "rock": 2, "steel": 2},
+	"flying":   {"electric": 0.5, "grass": 2, "fighting": 2, "bug": 2, "rock": 0.5, "steel": 0.5},
+	"psychic":  {"fighting": 2, "poison": 2, "psychic": 0.5, "dark": 0, "steel": 0.5},
+	"bug":      {"fire": 0.5, "grass": 2, "fighting": 0.5, "poison": 0.5, "flying": 0.5, "psychic": 2, "ghost": 0.5, "dark": 2, "steel": 0.5, "fairy": 0.5},
+	"rock":     {"fire": 2, "ice": 2, "fighting": 0.5, "ground": 0.5, "flying": 2, "bug": 2, "steel": 0.5},
+	"ghost":    {"normal": 0, "psychic": 2, "ghost": 2, "dark": 0.5},
+	"dragon":   {"dragon": 2, "steel": 0.5, "fairy": 0},
+	"dark":     {"fighting": 0.5, "psychic": 2, "ghost": 2, "dark": 0.5, "fairy": 0.5},
+	"steel":    {"fire": 0.5, "water": 0.5, "electric": 0.5, "ice": 2, "rock": 2, "steel": 0.5, "fairy": 2},
+	"fairy":    {"fire": 0.5, "fighting": 2, "poison": 0.5, "dragon": 2, "dark": 2, "steel": 0.5},
+}
+
+// abilityImmunities lists the abilities that make a Pokémon immune to attacking types
+var abilityImmunities = map[string][]string{
+	"levitate":        {"ground"},
+	"earth-eater":     {"ground"},
+	"flash-fire":      {"fire"},
+	"well-baked-body": {"fire"},
+	"volt-absorb":     {"electric"},
+	"lightning-rod":   {"electric"},
+	"motor-drive":     {"electric"},
+	"water-absorb":    {"water"},
+	"storm-drain":     {"water"},
+	"dry-skin":        {"water"},
+	"sap-sipper":      {"grass"},
+}
+
+// wonderGuard only lets super effective moves hit
+const wonderGuard = "wonder-guard"
+
+// index maps each type name to its row and column in the chart
+var index = make(map[string]int, len(Types))
+
+// chart is the 18x18 effectiveness matrix, indexed by attacking then defending type
+var chart [18][18]float64
+
+func init() {
+	for i, name := range Types {
+		index[name] = i
+	}
+
+	for i, attacking := range Types {
+		for j, defending := range Types {
+			chart[i][j] = 1
+			if multiplier, ok := exceptions[attacking][defending]; ok {
+				chart[i][j] = multiplier
+			}
+		}
+	}
+}
+
+// Valid reports whether the name is one of the 18 types
+func Valid(name string) bool {
+	_, ok := index[name]
+	return ok
+}
+
+// Effectiveness returns the damage multiplier of an attacking type against a single defending type
+func Effectiveness(attacking, defending string) (float64, error) {
+	i, ok := index[attacking]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownType, attacking)
+	}
+	j, ok := index[defending]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownType, defending)
+	}
+	return chart[i][j], nil
+}
+
+// Multiplier returns the damage multiplier of an attacking type against a
+// Pokémon with one or two types, e.g. 4 for ice against dragon/flying
+func Multiplier(attacking string, defending ...string) (float64, error) {
+	multiplier := 1.0
+	for _, def := range defending {
+		m, err := Effectiveness(attacking, def)
+		if err != nil {
+			return 0, err
+		}
+		multiplier *= m
+	}
+	return multiplier, nil
+}
+
+// Matchups returns the multiplier of every attacking type against the defending types
+func Matchups(defending ...string) (map[string]float64, error) {
+	matchups := make(map[string]float64, len(Types))
+	for _, attacking := range Types {
+		m, err := Multiplier(attacking, defending...)
+		if err != nil {
+			return nil, err
+		}
+		matchups[attacking] = m
+	}
+	return matchups, nil
+}
+
+// AbilityImmunities returns the attacking types an ability makes a Pokémon with
+// the defending types immune to. Wonder Guard blocks everything that is not
+// super effective. Abilities that grant no immunity return nil.
+func AbilityImmunities(ability string, defending ...string) ([]string, error) {
+	if ability != wonderGuard {
+		return abilityImmunities[ability], nil
+	}
+
+	var immune []string
+	for _, attacking := range Types {
+		m, err := Multiplier(attacking, defending...)
+		if err != nil {
+			return nil, err
+		}
+		if m > 0 && m < 2 {
+			immune = append(immune, attacking)
+		}
+	}
+	return immune, nil
+}
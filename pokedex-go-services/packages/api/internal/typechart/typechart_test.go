@@ -0,0 +1,113 @@
+package typechart
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEffectiveness(t *testing.T) {
+	tests := []struct {
+		attacking string
+		defending string
+		expected  float64
+	}{
+		{"fire", "grass", 2},
+		{"water", "water", 0.5},
+		{"normal", "ghost", 0},
+		{"dragon", "fairy", 0},
+		{"fairy", "dragon", 2},
+		{"electric", "normal", 1},
+		{"steel", "fairy", 2},
+	}
+
+	for _, tt := range tests {
+		got, err := Effectiveness(tt.attacking, tt.defending)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("Effectiveness(%s, %s) = %v, expected %v", tt.attacking, tt.defending, got, tt.expected)
+		}
+	}
+}
+
+func TestMultiplier_DualTypes(t *testing.T) {
+	tests := []struct {
+		attacking string
+		defending []string
+		expected  float64
+	}{
+		{"ice", []string{"dragon", "flying"}, 4},
+		{"electric", []string{"water", "flying"}, 4},
+		{"fighting", []string{"bug", "flying"}, 0.25},
+		{"ground", []string{"electric", "flying"}, 0},
+		{"water", []string{"fire", "ground"}, 4},
+		{"fire", []string{"water", "grass"}, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := Multiplier(tt.attacking, tt.defending...)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got != tt.expected {
+			t.Errorf("Multiplier(%s, %v) = %v, expected %v", tt.attacking, tt.defending, got, tt.expected)
+		}
+	}
+}
+
+func TestMultiplier_UnknownType(t *testing.T) {
+	if _, err := Multiplier("fire", "shadow"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+	if _, err := Effectiveness("stellar", "fire"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, name := range Types {
+		if !Valid(name) {
+			t.Errorf("Expected %s to be valid", name)
+		}
+	}
+	for _, name := range []string{"", "shadow", "Fire"} {
+		if Valid(name) {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestMatchups(t *testing.T) {
+	matchups, err := Matchups("grass", "poison")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(matchups) != len(Types) {
+		t.Fatalf("Expected %d matchups, got %d", len(Types), len(matchups))
+	}
+	if matchups["psychic"] != 2 || matchups["grass"] != 0.25 || matchups["fighting"] != 0.5 {
+		t.Errorf("Unexpected matchups %v", matchups)
+	}
+}
+
+func TestAbilityImmunities(t *testing.T) {
+	immune, _ := AbilityImmunities("levitate", "ghost", "poison")
+	if !reflect.DeepEqual(immune, []string{"ground"}) {
+		t.Errorf("Expected levitate to block ground, got %v", immune)
+	}
+
+	immune, _ = AbilityImmunities("overgrow", "grass")
+	if immune != nil {
+		t.Errorf("Expected no immunities, got %v", immune)
+	}
+
+	// Shedinja is bug/ghost: only fire, flying, rock, ghost and dark can hit it
+	immune, _ = AbilityImmunities("wonder-guard", "bug", "ghost")
+	expected := []string{"water", "electric", "grass", "ice", "poison", "ground", "psychic", "bug", "dragon", "steel", "fairy"}
+	if !reflect.DeepEqual(immune, expected) {
+		t.Errorf("Expected wonder guard to block %v, got %v", expected, immune)
+	}
+}
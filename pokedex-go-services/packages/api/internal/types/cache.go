@@ -0,0 +1,19 @@
+package types
+
+// CacheStats represents the current state of the Pokémon cache. Entries and
+// Capacity add up the caches of every kind of resource.
+type CacheStats struct {
+	Entries  int                       `json:"entries"`
+	Capacity int                       `json:"capacity"`
+	Kinds    map[string]CacheKindStats `json:"kinds"`
+	TTL      string                    `json:"ttl"`
+	Hits     uint64                    `json:"hits"`
+	Misses   uint64                    `json:"misses"`
+	Stale    uint64                    `json:"stale"`
+}
+
+// CacheKindStats represents the size of the cache of one kind of resource
+type CacheKindStats struct {
+	Entries  int `json:"entries"`
+	Capacity int `json:"capacity"`
+}
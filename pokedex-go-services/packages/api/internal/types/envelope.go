@@ -0,0 +1,27 @@
+package types
+
+// Envelope represents the body of every API response. Data is set on success
+// and Error on failure.
+type Envelope struct {
+	Success bool       `json:"success"`
+	Data    any        `json:"data,omitempty"`
+	Error   *ErrorBody `json:"error,omitempty"`
+	Meta    Meta       `json:"meta"`
+}
+
+// ErrorBody represents a failed request. Code is stable and meant for
+// programs; Message is in the client's language.
+type ErrorBody struct {
+	Code          string         `json:"code"`
+	Message       string         `json:"message"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	Details       map[string]any `json:"details,omitempty"`
+}
+
+// Meta represents information about how a response was produced
+type Meta struct {
+	RequestID  string      `json:"request_id"`
+	Cached     bool        `json:"cached"`
+	Source     string      `json:"source,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// UpstreamHealth represents the state of the connection to the PokeAPI
+type UpstreamHealth struct {
+	CircuitBreaker *BreakerStatus     `json:"circuit_breaker,omitempty"`
+	RateLimiter    *RateLimiterStatus `json:"rate_limiter,omitempty"`
+}
+
+// BreakerStatus represents the state of the circuit breaker around the PokeAPI
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+// RateLimiterStatus represents the outbound rate limiter and how long requests waited for it
+type RateLimiterStatus struct {
+	Limit         float64 `json:"limit_per_second"`
+	Burst         int     `json:"burst"`
+	Requests      uint64  `json:"requests"`
+	Delayed       uint64  `json:"delayed"`
+	Rejected      uint64  `json:"rejected"`
+	AverageWaitMS float64 `json:"average_wait_ms"`
+	MaxWaitMS     float64 `json:"max_wait_ms"`
+}
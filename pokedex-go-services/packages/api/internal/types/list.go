@@ -0,0 +1,19 @@
+package types
+
+// Pagination represents the position of a page in a listing. Next and Prev
+// are the URLs of the neighbouring pages, or null at either end.
+type Pagination struct {
+	Page       int     `json:"page"`
+	Limit      int     `json:"limit"`
+	Total      int     `json:"total"`
+	TotalPages int     `json:"total_pages"`
+	Next       *string `json:"next"`
+	Prev       *string `json:"prev"`
+}
+
+// PokemonName represents a Pokémon name matched by a search
+type PokemonName struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"`
+}
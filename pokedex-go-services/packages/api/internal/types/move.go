@@ -0,0 +1,36 @@
+package types
+
+// Move represents a move from the API
+type Move struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Accuracy    *int          `json:"accuracy"`
+	Power       *int          `json:"power"`
+	PP          *int          `json:"pp"`
+	Priority    int           `json:"priority"`
+	Type        NamedResource `json:"type"`
+	DamageClass NamedResource `json:"damage_class"`
+}
+
+// LearnsetResponse represents the moves a Pokémon learns in a version group
+type LearnsetResponse struct {
+	PokemonID    int           `json:"pokemon_id"`
+	Pokemon      string        `json:"pokemon"`
+	VersionGroup string        `json:"version_group"`
+	Method       string        `json:"method,omitempty"`
+	Moves        []LearnedMove `json:"moves"`
+}
+
+// LearnedMove represents a move in a learnset. Power, accuracy and PP are
+// null for moves that do not use them, e.g. status moves have no power.
+type LearnedMove struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Method      string `json:"method"`
+	Level       int    `json:"level"`
+	Type        string `json:"type"`
+	DamageClass string `json:"damage_class"`
+	Power       *int   `json:"power"`
+	Accuracy    *int   `json:"accuracy"`
+	PP          *int   `json:"pp"`
+}
@@ -2,20 +2,22 @@ package types
 
 // Pokemon represents a Pokémon from the API
 type Pokemon struct {
-	ID             int      `json:"id"`
-	Name           string   `json:"name"`
-	BaseExperience int      `json:"base_experience"`
-	Height         int      `json:"height"`
-	Weight         int      `json:"weight"`
-	Types          []Type   `json:"types"`
-	Stats          []Stat   `json:"stats"`
-	Abilities      []Ability `json:"abilities"`
-	Sprites        Sprites  `json:"sprites"`
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	BaseExperience int           `json:"base_experience"`
+	Height         int           `json:"height"`
+	Weight         int           `json:"weight"`
+	Types          []Type        `json:"types"`
+	Stats          []Stat        `json:"stats"`
+	Abilities      []Ability     `json:"abilities"`
+	Sprites        Sprites       `json:"sprites"`
+	Species        NamedResource `json:"species"`
+	Moves          []PokemonMove `json:"moves"`
 }
 
 // Type represents the type of a Pokémon
 type Type struct {
-	Slot int  `json:"slot"`
+	Slot int `json:"slot"`
 	Type struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
@@ -24,9 +26,9 @@ type Type struct {
 
 // Stat represents a stat of a Pokémon
 type Stat struct {
-	BaseStat  int `json:"base_stat"`
-	Effort    int `json:"effort"`
-	Stat struct {
+	BaseStat int `json:"base_stat"`
+	Effort   int `json:"effort"`
+	Stat     struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
 	} `json:"stat"`
@@ -42,6 +44,19 @@ type Ability struct {
 	Slot     int  `json:"slot"`
 }
 
+// PokemonMove represents a move a Pokémon can learn and how it learns it in each version group
+type PokemonMove struct {
+	Move                NamedResource       `json:"move"`
+	VersionGroupDetails []MoveVersionDetail `json:"version_group_details"`
+}
+
+// MoveVersionDetail represents how a move is learned in one version group
+type MoveVersionDetail struct {
+	LevelLearnedAt  int           `json:"level_learned_at"`
+	MoveLearnMethod NamedResource `json:"move_learn_method"`
+	VersionGroup    NamedResource `json:"version_group"`
+}
+
 // Sprites represents the images of a Pokémon
 type Sprites struct {
 	FrontDefault     string `json:"front_default"`
@@ -56,14 +71,18 @@ type Sprites struct {
 
 // PokemonResponse represents the simplified response for the frontend
 type PokemonResponse struct {
-	ID          int      `json:"id"`
-	Name        string   `json:"name"`
-	Types       []string `json:"types"`
-	Stats       Stats    `json:"stats"`
-	ImageURL    string   `json:"image_url"`
-	Height      int      `json:"height"`
-	Weight      int      `json:"weight"`
-	Abilities   []string `json:"abilities"`
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Types     []string `json:"types"`
+	Stats     Stats    `json:"stats"`
+	ImageURL  string   `json:"image_url"`
+	Height    int      `json:"height"`
+	Weight    int      `json:"weight"`
+	Abilities []string `json:"abilities"`
+
+	// Set when the client asks for a language
+	LocalizedName string `json:"localized_name,omitempty"`
+	Language      string `json:"language,omitempty"`
 }
 
 // Stats represents the organized stats
@@ -80,4 +99,4 @@ type Stats struct {
 type AIExplanation struct {
 	Explanation string `json:"explanation"`
 	GeneratedAt string `json:"generated_at"`
-} 
\ No newline at end of file
+}
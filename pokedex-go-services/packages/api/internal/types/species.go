@@ -0,0 +1,157 @@
+package types
+
+// NamedResource represents a reference to another PokeAPI resource
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// APIResource represents a reference to an unnamed PokeAPI resource
+type APIResource struct {
+	URL string `json:"url"`
+}
+
+// NamedResourceList represents a page of a PokeAPI resource listing
+type NamedResourceList struct {
+	Count   int             `json:"count"`
+	Results []NamedResource `json:"results"`
+}
+
+// PokemonSpecies represents a Pokémon species from the API
+type PokemonSpecies struct {
+	ID                 int               `json:"id"`
+	Name               string            `json:"name"`
+	IsBaby             bool              `json:"is_baby"`
+	IsLegendary        bool              `json:"is_legendary"`
+	IsMythical         bool              `json:"is_mythical"`
+	Generation         NamedResource     `json:"generation"`
+	Names              []LocalizedName   `json:"names"`
+	Genera             []Genus           `json:"genera"`
+	FlavorTextEntries  []FlavorTextEntry `json:"flavor_text_entries"`
+	EvolvesFromSpecies *NamedResource    `json:"evolves_from_species"`
+	EvolutionChain     APIResource       `json:"evolution_chain"`
+}
+
+// LocalizedName represents the name of a resource in one language
+type LocalizedName struct {
+	Name     string        `json:"name"`
+	Language NamedResource `json:"language"`
+}
+
+// Genus represents the category of a species in one language, e.g. "Mouse Pokémon"
+type Genus struct {
+	Genus    string        `json:"genus"`
+	Language NamedResource `json:"language"`
+}
+
+// FlavorTextEntry represents a Pokédex entry of a species in one game and language
+type FlavorTextEntry struct {
+	FlavorText string        `json:"flavor_text"`
+	Language   NamedResource `json:"language"`
+	Version    NamedResource `json:"version"`
+}
+
+// EvolutionChain represents an evolution chain from the API
+type EvolutionChain struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+// ChainLink represents a species in an evolution chain and what it evolves to
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// EvolutionDetail represents the conditions for a species to evolve
+type EvolutionDetail struct {
+	Trigger               NamedResource  `json:"trigger"`
+	Item                  *NamedResource `json:"item"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	Gender                *int           `json:"gender"`
+	MinLevel              *int           `json:"min_level"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+// SpeciesResponse represents the simplified species response for the frontend
+type SpeciesResponse struct {
+	ID          int                     `json:"id"`
+	Name        string                  `json:"name"`
+	Generation  string                  `json:"generation"`
+	IsBaby      bool                    `json:"is_baby"`
+	IsLegendary bool                    `json:"is_legendary"`
+	IsMythical  bool                    `json:"is_mythical"`
+	EvolvesFrom string                  `json:"evolves_from,omitempty"`
+	Names       map[string]string       `json:"names"`
+	Genus       map[string]string       `json:"genus"`
+	FlavorText  map[string][]FlavorText `json:"flavor_text"`
+	Language    string                  `json:"language,omitempty"`
+	Localized   *LocalizedSpecies       `json:"localized,omitempty"`
+}
+
+// LocalizedSpecies represents the species texts in the requested language.
+// Each text falls back to English when it has no translation.
+type LocalizedSpecies struct {
+	Name       string       `json:"name"`
+	Genus      string       `json:"genus"`
+	FlavorText []FlavorText `json:"flavor_text"`
+}
+
+// FlavorText represents a Pokédex entry in one game
+type FlavorText struct {
+	Version string `json:"version"`
+	Text    string `json:"text"`
+}
+
+// EvolutionChainResponse represents the resolved evolution tree of a species
+type EvolutionChainResponse struct {
+	ID    int           `json:"id"`
+	Chain EvolutionNode `json:"chain"`
+}
+
+// EvolutionNode represents a species in the evolution tree. Conditions
+// describe how it evolves from its parent and are empty for the root.
+type EvolutionNode struct {
+	SpeciesID  int                  `json:"species_id"`
+	Species    string               `json:"species"`
+	IsBaby     bool                 `json:"is_baby"`
+	Conditions []EvolutionCondition `json:"conditions,omitempty"`
+	EvolvesTo  []EvolutionNode      `json:"evolves_to"`
+}
+
+// EvolutionCondition represents one way of triggering an evolution. Only the
+// requirements that apply are set.
+type EvolutionCondition struct {
+	Trigger               string `json:"trigger"`
+	MinLevel              *int   `json:"min_level,omitempty"`
+	Item                  string `json:"item,omitempty"`
+	HeldItem              string `json:"held_item,omitempty"`
+	KnownMove             string `json:"known_move,omitempty"`
+	KnownMoveType         string `json:"known_move_type,omitempty"`
+	Location              string `json:"location,omitempty"`
+	PartySpecies          string `json:"party_species,omitempty"`
+	PartyType             string `json:"party_type,omitempty"`
+	TradeSpecies          string `json:"trade_species,omitempty"`
+	Gender                string `json:"gender,omitempty"`
+	MinHappiness          *int   `json:"min_happiness,omitempty"`
+	MinBeauty             *int   `json:"min_beauty,omitempty"`
+	MinAffection          *int   `json:"min_affection,omitempty"`
+	RelativePhysicalStats *int   `json:"relative_physical_stats,omitempty"`
+	TimeOfDay             string `json:"time_of_day,omitempty"`
+	NeedsOverworldRain    bool   `json:"needs_overworld_rain,omitempty"`
+	TurnUpsideDown        bool   `json:"turn_upside_down,omitempty"`
+}
@@ -0,0 +1,25 @@
+package types
+
+// WeaknessesResponse represents how much damage each attacking type deals to a
+// Pokémon, grouped by multiplier. Types that deal normal damage are only in Multipliers.
+type WeaknessesResponse struct {
+	PokemonID         int                `json:"pokemon_id"`
+	Pokemon           string             `json:"pokemon"`
+	Types             []string           `json:"types"`
+	Multipliers       map[string]float64 `json:"multipliers"`
+	Quadruple         []string           `json:"4x"`
+	Double            []string           `json:"2x"`
+	Half              []string           `json:"0.5x"`
+	Quarter           []string           `json:"0.25x"`
+	Immune            []string           `json:"0x"`
+	AbilityImmunities []AbilityImmunity  `json:"ability_immunities,omitempty"`
+}
+
+// AbilityImmunity represents an ability that makes the Pokémon immune to some
+// attacking types. The multipliers above do not account for it, since the
+// Pokémon may have a different ability.
+type AbilityImmunity struct {
+	Ability  string   `json:"ability"`
+	IsHidden bool     `json:"is_hidden"`
+	Types    []string `json:"types"`
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"pokedexia-backend/internal/api"
 	"pokedexia-backend/internal/config"
+	"pokedexia-backend/internal/services"
+	"pokedexia-backend/internal/store"
 )
 
 func main() {
@@ -16,13 +22,37 @@ func main() {
 		log.Println("Environment file not found, using system environment variables")
 	}
 
+	// Initialize the configuration
+	cfg := config.New()
+
+	// Run the requested subcommand
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		runSync(cfg, os.Args[2:])
+		return
+	}
+
+	runServer(cfg)
+}
+
+// runServer starts the HTTP API
+func runServer(cfg *config.Config) {
 	// Configure the Gin mode
 	if os.Getenv("GIN_MODE") == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	// Initialize the configuration
-	cfg := config.New()
+	// Open the local store of PokeAPI payloads
+	var st store.Store
+	if cfg.StorePath != "" {
+		boltStore, err := store.Open(cfg.StorePath)
+		if err != nil {
+			log.Fatal("Error opening local store:", err)
+		}
+		defer boltStore.Close()
+		st = boltStore
+	} else if cfg.Offline {
+		log.Fatal("OFFLINE mode requires STORE_PATH to be set")
+	}
 
 	// Create the router
 	router := gin.Default()
@@ -31,18 +61,19 @@ func main() {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
-		
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
+
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
 		}
-		
+
 		c.Next()
 	})
 
 	// Configure the routes
-	api.SetupRoutes(router, cfg)
+	api.SetupRoutes(router, cfg, st)
 
 	// Start the server
 	port := os.Getenv("PORT")
@@ -54,4 +85,73 @@ func main() {
 	if err := router.Run(":" + port); err != nil {
 		log.Fatal("Error starting server:", err)
 	}
-} 
\ No newline at end of file
+}
+
+// runSync mirrors the Pokédex into the local store
+func runSync(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("sync", flag.ExitOnError)
+	from := flags.Int("from", 1, "first Pokémon ID to sync")
+	to := flags.Int("to", 0, "last Pokémon ID to sync (0 for every Pokémon, alternate forms included)")
+	concurrency := flags.Int("concurrency", 4, "number of Pokémon fetched in parallel")
+	ratePerSecond := flags.Float64("rate", 10, "maximum PokeAPI requests per second (0 for unlimited)")
+	force := flags.Bool("force", false, "fetch Pokémon that are already in the store again")
+	flags.Parse(args)
+
+	if cfg.StorePath == "" {
+		log.Fatal("sync requires STORE_PATH to be set")
+	}
+
+	st, err := store.Open(cfg.StorePath)
+	if err != nil {
+		log.Fatal("Error opening local store:", err)
+	}
+	defer st.Close()
+
+	// Stop gracefully on Ctrl+C; a later run resumes from the store
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	service := services.NewPokeAPIService(cfg).WithStore(st)
+	opts := services.SyncOptions{
+		From:          *from,
+		To:            *to,
+		Concurrency:   *concurrency,
+		RatePerSecond: *ratePerSecond,
+		Force:         *force,
+	}
+
+	// Without an upper bound, sync every Pokémon the PokeAPI knows
+	if opts.To == 0 {
+		if err := service.RefreshIDs(ctx); err != nil {
+			log.Fatal("Error discovering valid Pokémon IDs:", err)
+		}
+		for _, id := range service.IDs().All() {
+			if id >= opts.From {
+				opts.IDs = append(opts.IDs, id)
+			}
+		}
+		if len(opts.IDs) == 0 {
+			log.Fatalf("No Pokémon IDs from %d", opts.From)
+		}
+		opts.To = opts.IDs[len(opts.IDs)-1]
+	}
+
+	log.Printf("Syncing Pokémon %d-%d into %s", opts.From, opts.To, cfg.StorePath)
+	result, err := service.Sync(ctx, opts, func(p services.SyncProgress) {
+		if p.Err != nil {
+			log.Printf("Error syncing Pokémon %d: %v", p.ID, p.Err)
+		}
+		if p.Done%50 == 0 || p.Done == p.Total {
+			log.Printf("Progress: %d/%d (fetched %d, skipped %d, failed %d)", p.Done, p.Total, p.Fetched, p.Skipped, p.Failed)
+		}
+	})
+	if err != nil {
+		log.Printf("Sync interrupted: %v", err)
+	}
+
+	log.Printf("Sync finished: fetched %d, skipped %d, failed %d", result.Fetched, result.Skipped, result.Failed)
+	if err != nil || result.Failed > 0 {
+		st.Close()
+		os.Exit(1)
+	}
+}